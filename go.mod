@@ -1,20 +1,29 @@
 module github.com/govinda-attal/kiss-lib
 
-go 1.12
+go 1.20
 
 require (
-	github.com/BurntSushi/toml v0.3.1 // indirect
-	github.com/asaskevich/govalidator v0.0.0-20180720115003-f9ffefc3facf // indirect
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/go-ozzo/ozzo-validation v3.5.0+incompatible
+	github.com/golang/protobuf v1.4.1
 	github.com/google/uuid v1.1.1
 	github.com/gorilla/mux v1.7.0
-	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	github.com/jinzhu/copier v0.0.0-20180308034124-7e38e58719c3
 	github.com/rs/cors v1.6.0
 	github.com/sirupsen/logrus v1.4.0
 	github.com/spf13/cobra v0.0.3
 	github.com/spf13/viper v1.3.2
 	github.com/urfave/negroni v1.0.0
+	github.com/vmihailenco/msgpack/v4 v4.3.12
 	golang.org/x/crypto v0.0.0-20190313024323-a1f597ede03a
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.27.0
+)
+
+require (
+	github.com/BurntSushi/toml v0.3.1 // indirect
+	github.com/asaskevich/govalidator v0.0.0-20180720115003-f9ffefc3facf // indirect
+	github.com/inconshreveable/mousetrap v1.0.0 // indirect
+	github.com/vmihailenco/tagparser v0.1.1 // indirect
+	golang.org/x/sys v0.0.0-20190215142949-d0b11bdaac8a // indirect
+	google.golang.org/protobuf v1.23.1-0.20200526195155-81db48ad09cc // indirect
 )
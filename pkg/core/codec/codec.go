@@ -0,0 +1,83 @@
+// Package codec provides encodings of payloads by content type, shared by HTTP binders and renderers and by asynchronous message handlers.
+// JSON, Protocol Buffers and MessagePack codecs are registered by default, more can be added with Register.
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Content types of the built-in codecs.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeMsgPack  = "application/msgpack"
+)
+
+// Codec encodes and decodes values of a content type.
+type Codec interface {
+	// ContentType returns the media type of the encoding.
+	ContentType() string
+	// Encode writes encoding of 'v' to 'w'.
+	Encode(w io.Writer, v interface{}) error
+	// Decode reads an encoded value from 'r' into 'v'.
+	Decode(r io.Reader, v interface{}) error
+}
+
+var (
+	mu     sync.RWMutex
+	codecs = make(map[string]Codec)
+)
+
+func init() {
+	Register(JSON)
+	Register(Protobuf, "application/protobuf", "application/vnd.google.protobuf")
+	Register(MsgPack, "application/x-msgpack", "application/vnd.msgpack")
+}
+
+// Register adds codec 'c' for its content type and given aliases, replacing a codec registered earlier for the same content type.
+func Register(c Codec, aliases ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	codecs[mediaType(c.ContentType())] = c
+	for _, a := range aliases {
+		codecs[mediaType(a)] = c
+	}
+}
+
+// ByContentType returns the codec registered for the media type of 'contentType', parameters like charset are ignored.
+func ByContentType(contentType string) (Codec, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := codecs[mediaType(contentType)]
+	return c, ok
+}
+
+// Marshal returns encoding of 'v' with the codec registered for 'contentType'.
+func Marshal(contentType string, v interface{}) ([]byte, error) {
+	c, ok := ByContentType(contentType)
+	if !ok {
+		return nil, fmt.Errorf("codec: no codec for content type %q", contentType)
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes 'data' into 'v' with the codec registered for 'contentType'.
+func Unmarshal(contentType string, data []byte, v interface{}) error {
+	c, ok := ByContentType(contentType)
+	if !ok {
+		return fmt.Errorf("codec: no codec for content type %q", contentType)
+	}
+	return c.Decode(bytes.NewReader(data), v)
+}
+
+func mediaType(contentType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+}
@@ -0,0 +1,32 @@
+package codec_test
+
+import (
+	"fmt"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/codec"
+)
+
+func ExampleUnmarshal() {
+	type quote struct {
+		Currency string  `msgpack:"currency"`
+		Amount   float64 `msgpack:"amount"`
+	}
+
+	b, err := codec.Marshal("application/msgpack", quote{"AUD", 12.5})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var q quote
+	if err := codec.Unmarshal("application/x-msgpack", b, &q); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(q.Currency, q.Amount)
+
+	_, err = codec.Marshal("application/x-protobuf", q)
+	fmt.Println(err)
+	// Output:
+	// AUD 12.5
+	// codec: codec_test.quote does not implement proto.Message
+}
@@ -0,0 +1,127 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+// Batch collects errors of the items of a batch operation, for example a batch endpoint or a batch Kafka handler,
+// and reports them as one error status. It is safe for concurrent use.
+type Batch struct {
+	mu    sync.Mutex
+	total int
+	items []itemErr
+}
+
+type itemErr struct {
+	index int
+	err   error
+}
+
+// NewBatch returns a batch for given number of items, total is used to tell partial failures from complete failures.
+// Use zero when the number of items is not known.
+func NewBatch(total int) *Batch {
+	return &Batch{total: total}
+}
+
+// Add records the error of the item at given index. Nil errors are ignored.
+func (b *Batch) Add(index int, err error) {
+	if err == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = append(b.items, itemErr{index, err})
+}
+
+// Len returns the number of failed items.
+func (b *Batch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.items)
+}
+
+// Err returns nil when no item failed, otherwise an error status with a detail for each failed item.
+// Details of failed items have field set to the index of the item, for example '[3]' or '[3].address.postcode'.
+// Overall code is worked out from failed items:
+//
+// a) 207 Multi-Status when some of the items succeeded,
+//
+// b) the code of the failed items when all of them failed with the same code,
+//
+// c) 400 Bad Request when all of them failed with client errors,
+//
+// d) 500 Internal Server Error otherwise.
+func (b *Batch) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	var dd []*StatusDtl
+	var errs batchErrs
+	code := codes.Success
+	for i, item := range b.items {
+		errSvc := Convert(item.err)
+		field := fmt.Sprintf("[%d]", item.index)
+		dd = append(dd, &StatusDtl{Field: field, Code: errSvc.Code.String(), Message: errSvc.Message})
+		for _, d := range errSvc.Details {
+			dx := *d
+			dx.Field = batchField(field, d.Field)
+			dd = append(dd, &dx)
+		}
+		errs = append(errs, item.err)
+		code = overallCode(code, errSvc.Code, i == 0)
+	}
+	if b.total > len(b.items) {
+		code = codes.ErrMultiStatus
+	}
+
+	msg := fmt.Sprintf("%d items failed", len(b.items))
+	if b.total > 0 {
+		msg = fmt.Sprintf("%d of %d items failed", len(b.items), b.total)
+	}
+	return newErr(code, 1).WithMessage(msg).WithDtl(dd...).WithError(errs)
+}
+
+func overallCode(code, itemCode codes.Code, first bool) codes.Code {
+	switch {
+	case first || code == itemCode:
+		return itemCode
+	case code.HTTPStatusCode() < 500 && itemCode.HTTPStatusCode() < 500:
+		return codes.ErrBadRequest
+	default:
+		return codes.ErrInternal
+	}
+}
+
+func batchField(itemField, field string) string {
+	switch {
+	case field == "":
+		return itemField
+	case strings.HasPrefix(field, "["):
+		return itemField + field
+	default:
+		return itemField + "." + field
+	}
+}
+
+// batchErrs are the errors of failed items, wrapped by the error status of a batch.
+type batchErrs []error
+
+func (be batchErrs) Error() string {
+	var ss []string
+	for _, err := range be {
+		ss = append(ss, errText(err))
+	}
+	return strings.Join(ss, "; ")
+}
+
+// Unwrap returns errors of the failed items, allowing errors.Is and errors.As to look through them.
+func (be batchErrs) Unwrap() []error {
+	return be
+}
@@ -1,9 +1,16 @@
 package codes
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
+
+	grpccodes "google.golang.org/grpc/codes"
 )
 
+// Code represents a status code. Each code is registered with a name, a default message, an HTTP status code and a retryable flag.
+// Code is marshalled with its stable name, for example "NOT_FOUND", so that reordering or adding codes doesn't change what clients see.
 type Code int
 
 const (
@@ -23,27 +30,280 @@ const (
 	ErrContentTypeNotSupported
 	// ErrStatusConflict represents conflict because of inconsistent or duplicated info.
 	ErrStatusConflict
+	// ErrForbidden represents a request that is not permitted for the authenticated subject.
+	ErrForbidden
+	// ErrTooManyRequests represents a request that was rejected because of rate limiting.
+	ErrTooManyRequests
+	// ErrPreconditionFailed represents a request whose preconditions, for example If-Match, were not met.
+	ErrPreconditionFailed
+	// ErrGone represents an error when a domain artifact is no longer available.
+	ErrGone
+	// ErrRequestTimeout represents a request that was not completed in time.
+	ErrRequestTimeout
+	// ErrServiceUnavailable represents a service that is temporarily unable to handle requests.
+	ErrServiceUnavailable
+	// ErrGatewayTimeout represents an upstream service that didn't respond in time.
+	ErrGatewayTimeout
+	// ErrUnprocessableEntity represents a well formed request that is semantically invalid.
+	ErrUnprocessableEntity
+	// ErrPayloadTooLarge represents a request with a body larger than permitted.
+	ErrPayloadTooLarge
+	// ErrMultiStatus represents a batch operation where some items failed and others succeeded.
+	ErrMultiStatus
+	// ErrNotAcceptable represents a request for a representation that the service cannot produce, as per the Accept header.
+	ErrNotAcceptable
 )
 
+// Def describes a registered code.
+type Def struct {
+	// Name is the stable name of the code, for example "PAYMENT_DECLINED".
+	Name string
+	// Message is the default message for the code.
+	Message string
+	// HTTPStatus is the HTTP status code that the code maps to.
+	HTTPStatus int
+	// GRPCCode is the gRPC status code that the code maps to. It is derived from HTTPStatus when not set.
+	GRPCCode grpccodes.Code
+	// Retryable reports whether an operation that failed with the code may succeed when retried.
+	Retryable bool
+}
+
+var registry = struct {
+	sync.RWMutex
+	defs  map[Code]Def
+	names map[string]Code
+	next  Code
+}{
+	defs:  make(map[Code]Def),
+	names: make(map[string]Code),
+}
+
+func init() {
+	registry.Lock()
+	defer registry.Unlock()
+	register(Success, Def{Name: "OK", Message: "OK", HTTPStatus: http.StatusOK, GRPCCode: grpccodes.OK})
+	register(ErrInternal, Def{Name: "INTERNAL", Message: "Internal Server Error", HTTPStatus: http.StatusInternalServerError, GRPCCode: grpccodes.Internal})
+	register(ErrUnauthorized, Def{Name: "UNAUTHORIZED", Message: "Unauthorized", HTTPStatus: http.StatusUnauthorized, GRPCCode: grpccodes.Unauthenticated})
+	register(ErrBadRequest, Def{Name: "BAD_REQUEST", Message: "Bad Request", HTTPStatus: http.StatusBadRequest, GRPCCode: grpccodes.InvalidArgument})
+	register(ErrNotImplemented, Def{Name: "NOT_IMPLEMENTED", Message: "Not Implemented", HTTPStatus: http.StatusNotImplemented, GRPCCode: grpccodes.Unimplemented})
+	register(ErrNotFound, Def{Name: "NOT_FOUND", Message: "Not Found", HTTPStatus: http.StatusNotFound, GRPCCode: grpccodes.NotFound})
+	register(ErrContentTypeNotSupported, Def{Name: "UNSUPPORTED_MEDIA_TYPE", Message: "Unsupported Media Type", HTTPStatus: http.StatusUnsupportedMediaType, GRPCCode: grpccodes.InvalidArgument})
+	register(ErrStatusConflict, Def{Name: "CONFLICT", Message: "Conflict because of inconsistent or duplicated info", HTTPStatus: http.StatusConflict, GRPCCode: grpccodes.AlreadyExists})
+	register(ErrForbidden, Def{Name: "FORBIDDEN", Message: "Forbidden", HTTPStatus: http.StatusForbidden, GRPCCode: grpccodes.PermissionDenied})
+	register(ErrTooManyRequests, Def{Name: "TOO_MANY_REQUESTS", Message: "Too Many Requests", HTTPStatus: http.StatusTooManyRequests, GRPCCode: grpccodes.ResourceExhausted, Retryable: true})
+	register(ErrPreconditionFailed, Def{Name: "PRECONDITION_FAILED", Message: "Precondition Failed", HTTPStatus: http.StatusPreconditionFailed, GRPCCode: grpccodes.FailedPrecondition})
+	register(ErrGone, Def{Name: "GONE", Message: "Gone", HTTPStatus: http.StatusGone, GRPCCode: grpccodes.NotFound})
+	register(ErrRequestTimeout, Def{Name: "REQUEST_TIMEOUT", Message: "Request Timeout", HTTPStatus: http.StatusRequestTimeout, GRPCCode: grpccodes.DeadlineExceeded, Retryable: true})
+	register(ErrServiceUnavailable, Def{Name: "SERVICE_UNAVAILABLE", Message: "Service Unavailable", HTTPStatus: http.StatusServiceUnavailable, GRPCCode: grpccodes.Unavailable, Retryable: true})
+	register(ErrGatewayTimeout, Def{Name: "GATEWAY_TIMEOUT", Message: "Gateway Timeout", HTTPStatus: http.StatusGatewayTimeout, GRPCCode: grpccodes.DeadlineExceeded, Retryable: true})
+	register(ErrUnprocessableEntity, Def{Name: "UNPROCESSABLE_ENTITY", Message: "Unprocessable Entity", HTTPStatus: http.StatusUnprocessableEntity, GRPCCode: grpccodes.InvalidArgument})
+	register(ErrPayloadTooLarge, Def{Name: "PAYLOAD_TOO_LARGE", Message: "Payload Too Large", HTTPStatus: http.StatusRequestEntityTooLarge, GRPCCode: grpccodes.ResourceExhausted})
+	register(ErrMultiStatus, Def{Name: "MULTI_STATUS", Message: "Multi-Status", HTTPStatus: http.StatusMultiStatus, GRPCCode: grpccodes.Unknown})
+	register(ErrNotAcceptable, Def{Name: "NOT_ACCEPTABLE", Message: "Not Acceptable", HTTPStatus: http.StatusNotAcceptable, GRPCCode: grpccodes.InvalidArgument})
+}
+
+// Register registers a new code with given definition and returns it.
+// It panics if the name is empty or already registered, hence it is best called to initialise package level variables.
+//
+//	var ErrPaymentDeclined = codes.Register(codes.Def{Name: "PAYMENT_DECLINED", Message: "Payment Declined", HTTPStatus: http.StatusPaymentRequired})
+func Register(d Def) Code {
+	registry.Lock()
+	defer registry.Unlock()
+	return register(registry.next, d)
+}
+
+// register must be called with registry lock held.
+func register(c Code, d Def) Code {
+	if d.Name == "" {
+		panic("codes: name of the code is required")
+	}
+	if _, ok := registry.names[d.Name]; ok {
+		panic(fmt.Sprintf("codes: code %s is already registered", d.Name))
+	}
+	if d.HTTPStatus == 0 {
+		d.HTTPStatus = http.StatusInternalServerError
+	}
+	if d.GRPCCode == grpccodes.OK && d.HTTPStatus >= http.StatusMultipleChoices {
+		d.GRPCCode = grpcCodeFromHTTP(d.HTTPStatus)
+	}
+	registry.defs[c] = d
+	registry.names[d.Name] = c
+	if c >= registry.next {
+		registry.next = c + 1
+	}
+	return c
+}
+
+// Lookup returns the definition of given code and whether the code is registered.
+func Lookup(c Code) (Def, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	d, ok := registry.defs[c]
+	return d, ok
+}
+
+// ByName returns the code registered with given name.
+func ByName(name string) (Code, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.names[name]
+	return c, ok
+}
+
+// Def returns the definition of the code.
+// Codes that are not registered are treated as internal server errors.
+func (c Code) Def() Def {
+	if d, ok := Lookup(c); ok {
+		return d
+	}
+	return Def{
+		Name:       strconv.Itoa(int(c)),
+		Message:    "Internal Server Error",
+		HTTPStatus: http.StatusInternalServerError,
+		GRPCCode:   grpccodes.Internal,
+	}
+}
+
+// String returns the name of the code.
+func (c Code) String() string {
+	return c.Def().Name
+}
+
+// Message returns the default message of the code.
+func (c Code) Message() string {
+	return c.Def().Message
+}
+
+// HTTPStatusCode returns the HTTP status code that the code maps to.
 func (c Code) HTTPStatusCode() int {
-	switch c {
-	case Success:
-		return http.StatusOK
-	case ErrInternal:
-		return http.StatusInternalServerError
-	case ErrUnauthorized:
-		return http.StatusUnauthorized
-	case ErrBadRequest:
-		return http.StatusBadRequest
-	case ErrNotImplemented:
-		return http.StatusNotImplemented
-	case ErrNotFound:
-		return http.StatusNotFound
-	case ErrContentTypeNotSupported:
-		return http.StatusUnsupportedMediaType
-	case ErrStatusConflict:
-		return http.StatusConflict
+	return c.Def().HTTPStatus
+}
+
+// GRPCCode returns the gRPC status code that best represents the code.
+func (c Code) GRPCCode() grpccodes.Code {
+	return c.Def().GRPCCode
+}
+
+// Retryable reports whether an operation that failed with the code may succeed when retried.
+func (c Code) Retryable() bool {
+	return c.Def().Retryable
+}
+
+// MarshalText marshals the code to its name.
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText unmarshals a code from its name.
+// Numeric text is accepted as is for codes that are not registered.
+func (c *Code) UnmarshalText(b []byte) error {
+	if v, ok := ByName(string(b)); ok {
+		*c = v
+		return nil
+	}
+	if v, err := strconv.Atoi(string(b)); err == nil {
+		*c = Code(v)
+		return nil
+	}
+	return fmt.Errorf("codes: unknown code %q", string(b))
+}
+
+// UnmarshalJSON unmarshals a code from its name or from a JSON number as it was marshalled in the past.
+func (c *Code) UnmarshalJSON(b []byte) error {
+	if len(b) > 1 && b[0] == '"' {
+		s, err := strconv.Unquote(string(b))
+		if err != nil {
+			return err
+		}
+		return c.UnmarshalText([]byte(s))
+	}
+	v, err := strconv.Atoi(string(b))
+	if err != nil {
+		return fmt.Errorf("codes: invalid code %s", string(b))
+	}
+	*c = Code(v)
+	return nil
+}
+
+// FromGRPCCode returns the code that best represents given gRPC status code.
+// It is used when a gRPC status does not carry the original code.
+func FromGRPCCode(gc grpccodes.Code) Code {
+	switch gc {
+	case grpccodes.OK:
+		return Success
+	case grpccodes.Unauthenticated:
+		return ErrUnauthorized
+	case grpccodes.InvalidArgument, grpccodes.OutOfRange:
+		return ErrBadRequest
+	case grpccodes.Unimplemented:
+		return ErrNotImplemented
+	case grpccodes.NotFound:
+		return ErrNotFound
+	case grpccodes.AlreadyExists, grpccodes.Aborted:
+		return ErrStatusConflict
+	case grpccodes.PermissionDenied:
+		return ErrForbidden
+	case grpccodes.ResourceExhausted:
+		return ErrTooManyRequests
+	case grpccodes.FailedPrecondition:
+		return ErrPreconditionFailed
+	case grpccodes.DeadlineExceeded:
+		return ErrGatewayTimeout
+	case grpccodes.Unavailable:
+		return ErrServiceUnavailable
 	default:
-		return http.StatusInternalServerError
+		return ErrInternal
+	}
+}
+
+// FromHTTPStatus returns the code that maps to given HTTP status code.
+// When more codes map to the same HTTP status code, the code registered first is returned.
+// Unknown client error status codes are treated as bad request and others as internal server error.
+func FromHTTPStatus(httpStatusCode int) Code {
+	registry.RLock()
+	defer registry.RUnlock()
+	found := false
+	var code Code
+	for c, d := range registry.defs {
+		if d.HTTPStatus == httpStatusCode && (!found || c < code) {
+			code, found = c, true
+		}
+	}
+	switch {
+	case found:
+		return code
+	case httpStatusCode < http.StatusBadRequest:
+		return Success
+	case httpStatusCode < http.StatusInternalServerError:
+		return ErrBadRequest
+	default:
+		return ErrInternal
+	}
+}
+
+func grpcCodeFromHTTP(httpStatusCode int) grpccodes.Code {
+	switch httpStatusCode {
+	case http.StatusBadRequest:
+		return grpccodes.InvalidArgument
+	case http.StatusUnauthorized:
+		return grpccodes.Unauthenticated
+	case http.StatusForbidden:
+		return grpccodes.PermissionDenied
+	case http.StatusNotFound:
+		return grpccodes.NotFound
+	case http.StatusConflict:
+		return grpccodes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return grpccodes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return grpccodes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return grpccodes.Unavailable
+	case http.StatusGatewayTimeout:
+		return grpccodes.DeadlineExceeded
+	}
+	if httpStatusCode < http.StatusInternalServerError {
+		return grpccodes.FailedPrecondition
 	}
+	return grpccodes.Internal
 }
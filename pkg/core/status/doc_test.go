@@ -0,0 +1,195 @@
+package status_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+func ExampleErrServiceStatus_GRPCStatus() {
+	errSvc := status.ErrNotFound().WithMessage("customer")
+	errSvc.AddDtl("C01", "customer id is unknown")
+
+	// gRPC service methods can return errSvc as is, gRPC clients can rebuild it from the returned error.
+	s := errSvc.GRPCStatus()
+	fmt.Println(s.Code(), s.Message())
+
+	e := status.FromGRPCError(s.Err())
+	fmt.Println(e.Code == errSvc.Code, e.Message, e.Details[0].Code, e.Details[0].Message)
+	// Output:
+	// NotFound Not Found: customer
+	// true Not Found: customer C01 customer id is unknown
+}
+
+func ExampleCodeOf() {
+	err := fmt.Errorf("loading customer: %w", status.ErrNotFound())
+
+	fmt.Println(errors.Is(err, status.ErrNotFound()))
+	fmt.Println(status.CodeOf(err) == codes.ErrNotFound)
+	fmt.Println(status.CodeOf(errors.New("connection refused")) == codes.ErrInternal)
+	// Output:
+	// true
+	// true
+	// true
+}
+
+var errPaymentDeclined = codes.Register(codes.Def{
+	Name:       "PAYMENT_DECLINED",
+	Message:    "Payment Declined",
+	HTTPStatus: http.StatusPaymentRequired,
+})
+
+func ExampleNew_registeredCode() {
+	errSvc := status.New(errPaymentDeclined).WithMessage("card expired")
+
+	b, _ := json.Marshal(errSvc)
+	fmt.Println(string(b))
+	fmt.Println(errSvc.Code.HTTPStatusCode(), errSvc.Code.GRPCCode())
+	// Output:
+	// {"code":"PAYMENT_DECLINED","msg":"Payment Declined: card expired"}
+	// 402 FailedPrecondition
+}
+
+func ExampleErrServiceStatus_LogFields() {
+	errSvc := status.ErrInternal().
+		WithInternalMessage("begin transaction").
+		WithError(errors.New("dial tcp 10.0.0.7:5432: connection refused"))
+
+	// Only client safe parts are rendered.
+	b, _ := json.Marshal(errSvc)
+	fmt.Println(string(b))
+
+	// Loggers get the full detail.
+	f := errSvc.LogFields()
+	fmt.Println(f["status_msg"], "-", f["internal"], "-", f["cause"])
+	// Output:
+	// {"code":"INTERNAL","msg":"Internal Server Error"}
+	// Internal Server Error - begin transaction - dial tcp 10.0.0.7:5432: connection refused
+}
+
+func ExampleParse() {
+	// Error status returned by another service.
+	errSvc := status.Parse(http.StatusNotFound, []byte(`{"code":"NOT_FOUND","msg":"Not Found: customer"}`))
+	fmt.Println(errSvc.Code.HTTPStatusCode(), errSvc.Message)
+
+	// Problem details returned by another service.
+	errSvc = status.Parse(http.StatusConflict, []byte(`{"type":"about:blank","title":"Conflict","status":409,"detail":"order already placed"}`))
+	fmt.Println(errSvc.Code.HTTPStatusCode(), errSvc.Message)
+
+	// Body that is not an error status.
+	errSvc = status.Parse(http.StatusBadGateway, []byte(`<html>Bad Gateway</html>`))
+	fmt.Println(errSvc.Code.HTTPStatusCode(), errSvc.Message)
+	// Output:
+	// 404 Not Found: customer
+	// 409 order already placed
+	// 500 Bad Gateway
+}
+
+func ExampleBatch() {
+	orders := []string{"A-1", "", "A-3", "A-4"}
+
+	b := status.NewBatch(len(orders))
+	for i, o := range orders {
+		if o == "" {
+			errSvc := status.ErrBadRequest()
+			errSvc.AddFieldDtl("orderID", "validation_required", "cannot be blank")
+			b.Add(i, errSvc)
+			continue
+		}
+		if o == "A-4" {
+			b.Add(i, status.ErrNotFound().WithMessage("order"))
+		}
+	}
+
+	errSvc, _ := status.FromError(b.Err())
+	fmt.Println(errSvc.Code.HTTPStatusCode(), errSvc.Message)
+	for _, d := range errSvc.Details {
+		fmt.Println(d.Field, d.Code, d.Message)
+	}
+	// Output:
+	// 207 Multi-Status: 2 of 4 items failed
+	// [1] BAD_REQUEST Bad Request
+	// [1].orderID validation_required cannot be blank
+	// [3] NOT_FOUND Not Found: order
+}
+
+func ExampleSetStackMode() {
+	defer status.SetStackMode(status.StackNone)
+
+	errSvc := status.ErrInternal()
+	fmt.Println(len(errSvc.StackTrace()), errSvc.Origin() == "")
+
+	status.SetStackMode(status.StackOrigin)
+	errSvc = status.ErrInternal()
+	ff := errSvc.StackTrace()
+	fmt.Println(len(ff), ff[0].Function)
+	fmt.Println(strings.HasPrefix(errSvc.Origin(), ff[0].Function+" "))
+
+	status.SetStackMode(status.StackFull)
+	errSvc = status.ErrInternal()
+	ff = errSvc.StackTrace()
+	fmt.Println(len(ff) > 1, ff[0].Function)
+	// Output:
+	// 0 true
+	// 1 github.com/govinda-attal/kiss-lib/pkg/core/status_test.ExampleSetStackMode
+	// true
+	// true github.com/govinda-attal/kiss-lib/pkg/core/status_test.ExampleSetStackMode
+}
+
+func ExampleSetStackMode_withError() {
+	defer status.SetStackMode(status.StackNone)
+
+	// Without capture, wrapping leaves the stack empty.
+	errSvc := status.ErrInternal().WithError(errors.New("disk full"))
+	_, ok := errSvc.LogFields()["stack"]
+	fmt.Println(ok)
+
+	status.SetStackMode(status.StackOrigin)
+	errSvc = wrap(errors.New("disk full"))
+	f := errSvc.LogFields()
+	fmt.Println(len(f["stack"].([]string)), strings.HasPrefix(f["origin"].(string), "github.com/govinda-attal/kiss-lib/pkg/core/status_test.wrap "))
+	// Output:
+	// false
+	// 1 true
+}
+
+func wrap(err error) status.ErrServiceStatus {
+	return status.ErrInternal().WithError(err)
+}
+
+func ExampleErrServiceStatus_Format() {
+	defer status.SetStackMode(status.StackNone)
+
+	errSvc := status.ErrNotFound().WithMessage("order missing").WithError(errors.New("no rows"))
+	fmt.Printf("%v\n", errSvc)
+	fmt.Printf("%s\n", errSvc)
+	fmt.Printf("%q\n", errSvc)
+	fmt.Printf("%d\n", errSvc)
+	fmt.Printf("%+v\n", errSvc)
+
+	status.SetStackMode(status.StackOrigin)
+	errSvc = status.ErrNotFound().WithMessage("order missing")
+	ll := strings.Split(fmt.Sprintf("%+v", errSvc), "\n")
+	fmt.Println(len(ll), ll[1])
+	// Output:
+	// {"code":"NOT_FOUND","msg":"Not Found: order missing"}
+	// {"code":"NOT_FOUND","msg":"Not Found: order missing"}
+	// "{\"code\":\"NOT_FOUND\",\"msg\":\"Not Found: order missing\"}"
+	// %!d({"code":"NOT_FOUND","msg":"Not Found: order missing"})
+	// NOT_FOUND: Not Found: order missing: no rows
+	// 3 github.com/govinda-attal/kiss-lib/pkg/core/status_test.ExampleErrServiceStatus_Format
+}
+
+func ExampleErrServiceStatus_WithRetryAfter() {
+	fmt.Println(status.ErrTooManyRequests().WithRetryAfter(1500 * time.Millisecond).Meta())
+	fmt.Println(status.ErrTooManyRequests().WithRetryAfter(-time.Second).Meta())
+	// Output:
+	// map[Retry-After:2]
+	// map[]
+}
@@ -0,0 +1,94 @@
+package status
+
+import (
+	"encoding/json"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+const (
+	// grpcDomainStatus is the error info domain that carries the code of an error status.
+	grpcDomainStatus = "kiss-lib/status"
+	// grpcDomainDtl is the error info domain that carries a status detail.
+	grpcDomainDtl = "kiss-lib/status/dtl"
+)
+
+// GRPCStatus returns a gRPC status for the error status.
+// Code, details and retry after metadata are carried as error details, so that FromGRPCStatus can rebuild the error status unchanged.
+// As it satisfies the interface expected by gRPC, an ErrServiceStatus can be returned as is from gRPC service methods.
+func (e ErrServiceStatus) GRPCStatus() *grpcstatus.Status {
+	s := grpcstatus.New(e.Code.GRPCCode(), e.Message)
+	dd := []proto.Message{
+		&errdetails.ErrorInfo{Reason: e.Code.String(), Domain: grpcDomainStatus},
+	}
+	if delay, ok := e.RetryAfter(); ok {
+		dd = append(dd, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(delay)})
+	}
+	for _, d := range e.Details {
+		md := map[string]string{"msg": d.Message}
+		if d.Field != "" {
+			md["field"] = d.Field
+		}
+		if len(d.Params) > 0 {
+			if b, err := json.Marshal(d.Params); err == nil {
+				md["params"] = string(b)
+			}
+		}
+		dd = append(dd, &errdetails.ErrorInfo{Reason: d.Code, Domain: grpcDomainDtl, Metadata: md})
+	}
+	sd, err := s.WithDetails(dd...)
+	if err != nil {
+		return s
+	}
+	return sd
+}
+
+// FromGRPCStatus returns an error status for given gRPC status.
+// When the gRPC status was not created by GRPCStatus, code is derived from the gRPC status code.
+func FromGRPCStatus(s *grpcstatus.Status) ErrServiceStatus {
+	e := ErrServiceStatus{
+		ServiceStatus: ServiceStatus{Code: codes.FromGRPCCode(s.Code()), Message: s.Message()},
+		err:           s.Err(),
+	}
+	for _, d := range s.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			if delay, err := ptypes.Duration(ri.RetryDelay); err == nil {
+				e = e.WithRetryAfter(delay)
+			}
+			continue
+		}
+		ei, ok := d.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		switch ei.Domain {
+		case grpcDomainStatus:
+			var c codes.Code
+			if err := c.UnmarshalText([]byte(ei.Reason)); err == nil {
+				e.Code = c
+			}
+		case grpcDomainDtl:
+			d := &StatusDtl{Field: ei.Metadata["field"], Code: ei.Reason, Message: ei.Metadata["msg"]}
+			if p, ok := ei.Metadata["params"]; ok {
+				json.Unmarshal([]byte(p), &d.Params)
+			}
+			e.Details = append(e.Details, d)
+		}
+	}
+	return e
+}
+
+// FromGRPCError returns an error status for given error returned by a gRPC client.
+// Errors that don't carry a gRPC status are converted with Convert.
+func FromGRPCError(err error) ErrServiceStatus {
+	s, ok := grpcstatus.FromError(err)
+	if !ok {
+		return Convert(err)
+	}
+	return FromGRPCStatus(s)
+}
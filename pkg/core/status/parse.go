@@ -0,0 +1,101 @@
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+// maxParseBodySize restricts how much of a HTTP response body is read by FromHTTPResponse.
+const maxParseBodySize = 1 << 20
+
+// errStatusMsg is the union of error status {code, msg, details} and problem details (RFC 7807) messages.
+type errStatusMsg struct {
+	Code    json.RawMessage `json:"code"`
+	Message string          `json:"msg"`
+	Details []*StatusDtl    `json:"details"`
+	Type    string          `json:"type"`
+	Title   string          `json:"title"`
+	Status  int             `json:"status"`
+	Detail  string          `json:"detail"`
+}
+
+// FromBytes returns the error status marshalled as JSON in given bytes.
+// It understands both error status {code, msg, details} and problem details (RFC 7807),
+// hence it can be used for HTTP response bodies as well as for events on a Kafka error topic.
+func FromBytes(b []byte) (ErrServiceStatus, error) {
+	return fromBytes(0, b)
+}
+
+// Parse returns the error status for given HTTP status code and response body.
+// When body is not an error status, code is derived from HTTP status code and message from its status text.
+func Parse(httpStatusCode int, body []byte) ErrServiceStatus {
+	errSvc, err := fromBytes(httpStatusCode, body)
+	if err != nil {
+		errSvc = New(codes.FromHTTPStatus(httpStatusCode))
+		if txt := http.StatusText(httpStatusCode); txt != "" {
+			errSvc.Message = txt
+		}
+		return errSvc.WithError(err)
+	}
+	return errSvc
+}
+
+// FromHTTPResponse reads and closes the body of given HTTP response and returns its error status.
+// It is meant for unsuccessful responses from services that the application calls,
+// so that for example an upstream 404 is returned as a 404 by the application handlers too.
+// Retry-After header is kept as metadata of the error status.
+func FromHTTPResponse(rs *http.Response) ErrServiceStatus {
+	defer rs.Body.Close()
+	body, err := ioutil.ReadAll(io.LimitReader(rs.Body, maxParseBodySize))
+	if err != nil {
+		return New(codes.FromHTTPStatus(rs.StatusCode)).WithInternalMessage("error reading http response").WithError(err)
+	}
+	errSvc := Parse(rs.StatusCode, body)
+	if ra := rs.Header.Get(MetaRetryAfter); ra != "" {
+		errSvc = errSvc.WithMeta(MetaRetryAfter, ra)
+	}
+	return errSvc
+}
+
+func fromBytes(httpStatusCode int, b []byte) (ErrServiceStatus, error) {
+	var msg errStatusMsg
+	if err := json.Unmarshal(b, &msg); err != nil {
+		return ErrServiceStatus{}, err
+	}
+	if msg.Status != 0 {
+		httpStatusCode = msg.Status
+	}
+
+	var errSvc ErrServiceStatus
+	var code codes.Code
+	switch err := code.UnmarshalJSON(msg.Code); {
+	case len(msg.Code) > 0 && err == nil:
+		errSvc = New(code)
+	case msg.Status != 0 || httpStatusCode != 0:
+		errSvc = New(codes.FromHTTPStatus(httpStatusCode))
+		if len(msg.Code) > 0 {
+			errSvc = errSvc.WithInternalMessage(fmt.Sprintf("unknown code %s", msg.Code))
+		}
+	default:
+		return ErrServiceStatus{}, errors.New("status: neither code nor http status code is available")
+	}
+
+	switch {
+	case msg.Message != "":
+		errSvc.Message = msg.Message
+	case msg.Detail != "":
+		errSvc.Message = msg.Detail
+	case msg.Title != "":
+		errSvc.Message = msg.Title
+	case msg.Type == "" && len(msg.Code) == 0:
+		return ErrServiceStatus{}, errors.New("status: not an error status")
+	}
+	errSvc.Details = msg.Details
+	return errSvc, nil
+}
@@ -0,0 +1,37 @@
+package status
+
+import (
+	"net/http"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+// ProblemTypeDefault is the problem type used when the problem has no additional semantics beyond the HTTP status code.
+const ProblemTypeDefault = "about:blank"
+
+// Problem captures problem details for HTTP APIs as per RFC 7807 (application/problem+json).
+// Code and Details of the error status are carried as extension members.
+type Problem struct {
+	Type     string       `json:"type" xml:"type"`
+	Title    string       `json:"title,omitempty" xml:"title,omitempty"`
+	Status   int          `json:"status,omitempty" xml:"status,omitempty"`
+	Detail   string       `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty" xml:"instance,omitempty"`
+	Code     codes.Code   `json:"code" xml:"code"`
+	Details  []*StatusDtl `json:"details,omitempty" xml:"details>detail,omitempty"`
+}
+
+// Problem returns problem details for the error status.
+// Instance identifies the specific occurrence of the problem, for example a request ID.
+func (e ErrServiceStatus) Problem(instance string) Problem {
+	httpStatusCode := e.Code.HTTPStatusCode()
+	return Problem{
+		Type:     ProblemTypeDefault,
+		Title:    http.StatusText(httpStatusCode),
+		Status:   httpStatusCode,
+		Detail:   e.Message,
+		Instance: instance,
+		Code:     e.Code,
+		Details:  e.Details,
+	}
+}
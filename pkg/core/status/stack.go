@@ -0,0 +1,107 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// StackMode controls what is captured when an error status is created or wrapped with WithError.
+type StackMode int32
+
+const (
+	// StackNone disables capture. This is the default.
+	StackNone StackMode = iota
+	// StackOrigin captures only the frame where the error status was created.
+	StackOrigin
+	// StackFull captures the call stack where the error status was created.
+	StackFull
+)
+
+const maxStackDepth = 32
+
+var stackMode int32
+
+// SetStackMode sets what is captured when an error status is created or wrapped with WithError.
+// Captured stack is available with %+v and LogFields, it is never rendered to clients.
+// It is best set in main package at bootstrap.
+func SetStackMode(m StackMode) {
+	atomic.StoreInt32(&stackMode, int32(m))
+}
+
+type stack []uintptr
+
+// callers captures the stack as per stack mode, skip is the number of callers to skip.
+func callers(skip int) stack {
+	depth := maxStackDepth
+	switch StackMode(atomic.LoadInt32(&stackMode)) {
+	case StackNone:
+		return nil
+	case StackOrigin:
+		depth = 1
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	return stack(pcs[:n])
+}
+
+func (s stack) frames() []runtime.Frame {
+	var ff []runtime.Frame
+	frames := runtime.CallersFrames(s)
+	for {
+		f, more := frames.Next()
+		if f.Function != "" {
+			ff = append(ff, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return ff
+}
+
+func (s stack) lines() []string {
+	var ll []string
+	for _, f := range s.frames() {
+		ll = append(ll, fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line))
+	}
+	return ll
+}
+
+// StackTrace returns frames of the stack captured when the error status was created or wrapped.
+// It is empty unless enabled with SetStackMode.
+func (e ErrServiceStatus) StackTrace() []runtime.Frame {
+	return e.stack.frames()
+}
+
+// Origin returns the function, file and line where the error status was created or wrapped.
+// It is empty unless enabled with SetStackMode.
+func (e ErrServiceStatus) Origin() string {
+	if ll := e.stack.lines(); len(ll) > 0 {
+		return ll[0]
+	}
+	return ""
+}
+
+// Format formats the error status, %s and %v print the same as Error(), %q quotes it.
+// %+v prints code, message, internal message and causes followed by the captured stack.
+func (e ErrServiceStatus) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.Code.String()+": "+errText(e))
+			for _, f := range e.stack.frames() {
+				fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+			}
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, e.Error())
+	}
+}
@@ -0,0 +1,120 @@
+package status
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+// xmlStatus is the XML form of ServiceStatus, details are wrapped so that no details element is rendered without details.
+type xmlStatus struct {
+	Code    codes.Code `xml:"code,omitempty"`
+	Message string     `xml:"msg,omitempty"`
+	Details *xmlDtls   `xml:"details,omitempty"`
+}
+
+// xmlProblem is the XML form of Problem as per RFC 7807.
+type xmlProblem struct {
+	XMLName  xml.Name   `xml:"urn:ietf:rfc:7807 problem"`
+	Type     string     `xml:"type"`
+	Title    string     `xml:"title,omitempty"`
+	Status   int        `xml:"status,omitempty"`
+	Detail   string     `xml:"detail,omitempty"`
+	Instance string     `xml:"instance,omitempty"`
+	Code     codes.Code `xml:"code"`
+	Details  *xmlDtls   `xml:"details,omitempty"`
+}
+
+type xmlDtls struct {
+	Dtls []*StatusDtl `xml:"detail"`
+}
+
+func newXMLDtls(dd []*StatusDtl) *xmlDtls {
+	if len(dd) == 0 {
+		return nil
+	}
+	return &xmlDtls{dd}
+}
+
+// MarshalXML renders the status as XML, for example <status><code>NOT_FOUND</code><msg>Not Found</msg></status>.
+// The root element is named 'status' unless the status is rendered as a named element of another value.
+func (s ServiceStatus) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "ServiceStatus" || start.Name.Local == "ErrServiceStatus" {
+		start.Name = xml.Name{Local: "status"}
+	}
+	return e.EncodeElement(xmlStatus{Code: s.Code, Message: s.Message, Details: newXMLDtls(s.Details)}, start)
+}
+
+// MarshalXML renders the problem as XML (application/problem+xml) in the namespace of RFC 7807.
+func (p Problem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.Encode(xmlProblem{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+		Code:     p.Code,
+		Details:  newXMLDtls(p.Details),
+	})
+}
+
+// xmlDtl is the XML form of StatusDtl, parameters are rendered as elements named by an attribute as XML has no maps.
+type xmlDtl struct {
+	Field   string     `xml:"field,omitempty"`
+	Code    string     `xml:"code,omitempty"`
+	Message string     `xml:"msg,omitempty"`
+	Params  []xmlParam `xml:"params>param,omitempty"`
+}
+
+type xmlParam struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML renders the detail as XML, for example <detail><field>name</field><params><param name="min">3</param></params></detail>.
+// Parameters are ordered by name, values that are not scalar are rendered as JSON.
+func (d StatusDtl) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	x := xmlDtl{Field: d.Field, Code: d.Code, Message: d.Message}
+	names := make([]string, 0, len(d.Params))
+	for k := range d.Params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		x.Params = append(x.Params, xmlParam{Name: k, Value: paramText(d.Params[k])})
+	}
+	return e.EncodeElement(x, start)
+}
+
+// UnmarshalXML reads the detail from XML rendered by MarshalXML, parameter values are read as strings.
+func (d *StatusDtl) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var x xmlDtl
+	if err := dec.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	*d = StatusDtl{Field: x.Field, Code: x.Code, Message: x.Message}
+	for _, p := range x.Params {
+		if d.Params == nil {
+			d.Params = make(map[string]interface{}, len(x.Params))
+		}
+		d.Params[p.Name] = p.Value
+	}
+	return nil
+}
+
+func paramText(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return fmt.Sprint(v)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
@@ -2,108 +2,342 @@ package status
 
 import (
 	"encoding/json"
+	"errors"
+	"strconv"
 	"strings"
-
-	"github.com/jinzhu/copier"
+	"time"
 
 	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
 )
 
-// ErrInternal represents internal server error.
-func ErrInternal() ErrServiceStatus {
+// MetaRetryAfter is the metadata key, and the HTTP header, that tells clients when to retry.
+const MetaRetryAfter = "Retry-After"
+
+// New returns an error status with given code and its default message.
+// It can be used for domain specific codes registered with codes.Register.
+func New(code codes.Code) ErrServiceStatus {
+	return newErr(code, 1)
+}
+
+// newErr returns an error status with given code, skip is the number of callers to skip before capturing the stack.
+func newErr(code codes.Code, skip int) ErrServiceStatus {
 	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrInternal, Message: "Internal Server Error"}, nil,
+		ServiceStatus: ServiceStatus{Code: code, Message: code.Message()},
+		stack:         callers(skip + 1),
 	}
 }
 
+// ErrInternal represents internal server error.
+func ErrInternal() ErrServiceStatus {
+	return newErr(codes.ErrInternal, 1)
+}
+
 // ErrUnauthorized represents an unauthorized request error.
 func ErrUnauthorized() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrUnauthorized, Message: "Unauthorized"}, nil,
-	}
+	return newErr(codes.ErrUnauthorized, 1)
 }
 
 // ErrNotFound represents an error when a domain artifact was not found.
 func ErrNotFound() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrNotFound, Message: "Not Found"}, nil,
-	}
+	return newErr(codes.ErrNotFound, 1)
 }
 
 // ErrBadRequest represents an invalid request error.
 func ErrBadRequest() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrBadRequest, Message: "Bad Request"}, nil,
-	}
+	return newErr(codes.ErrBadRequest, 1)
 }
 
 // ErrNotImplemented represents an unauthorized request error.
 func ErrNotImplemented() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrNotImplemented, Message: "Not Implemented"}, nil,
-	}
+	return newErr(codes.ErrNotImplemented, 1)
 }
 
 // ErrContentTypeNotSupported represents unsupported media type.
 func ErrContentTypeNotSupported() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrContentTypeNotSupported, Message: "Unsupported Media Type"}, nil,
-	}
+	return newErr(codes.ErrContentTypeNotSupported, 1)
 }
 
 // ErrStatusConflict represents conflict because of inconsistent or duplicated info.
 func ErrStatusConflict() ErrServiceStatus {
-	return ErrServiceStatus{
-		ServiceStatus{Code: codes.ErrStatusConflict, Message: "Conflict because of inconsistent or duplicated info"}, nil,
-	}
+	return newErr(codes.ErrStatusConflict, 1)
+}
+
+// ErrForbidden represents a request that is not permitted for the authenticated subject.
+func ErrForbidden() ErrServiceStatus {
+	return newErr(codes.ErrForbidden, 1)
+}
+
+// ErrTooManyRequests represents a request that was rejected because of rate limiting.
+// Use WithRetryAfter to let clients know when to retry.
+func ErrTooManyRequests() ErrServiceStatus {
+	return newErr(codes.ErrTooManyRequests, 1)
+}
+
+// ErrPreconditionFailed represents a request whose preconditions, for example If-Match, were not met.
+func ErrPreconditionFailed() ErrServiceStatus {
+	return newErr(codes.ErrPreconditionFailed, 1)
+}
+
+// ErrGone represents an error when a domain artifact is no longer available.
+func ErrGone() ErrServiceStatus {
+	return newErr(codes.ErrGone, 1)
+}
+
+// ErrRequestTimeout represents a request that was not completed in time.
+func ErrRequestTimeout() ErrServiceStatus {
+	return newErr(codes.ErrRequestTimeout, 1)
+}
+
+// ErrServiceUnavailable represents a service that is temporarily unable to handle requests.
+// Use WithRetryAfter to let clients know when to retry.
+func ErrServiceUnavailable() ErrServiceStatus {
+	return newErr(codes.ErrServiceUnavailable, 1)
 }
 
+// ErrGatewayTimeout represents an upstream service that didn't respond in time.
+func ErrGatewayTimeout() ErrServiceStatus {
+	return newErr(codes.ErrGatewayTimeout, 1)
+}
+
+// ErrUnprocessableEntity represents a well formed request that is semantically invalid.
+func ErrUnprocessableEntity() ErrServiceStatus {
+	return newErr(codes.ErrUnprocessableEntity, 1)
+}
+
+// ErrPayloadTooLarge represents a request with a body larger than permitted.
+func ErrPayloadTooLarge() ErrServiceStatus {
+	return newErr(codes.ErrPayloadTooLarge, 1)
+}
+
+// ErrNotAcceptable represents a request for a representation that the service cannot produce, as per the Accept header.
+func ErrNotAcceptable() ErrServiceStatus {
+	return newErr(codes.ErrNotAcceptable, 1)
+}
+
+// ErrCause returns the root cause of given error by walking through its chain of causes.
+// Both Cause() and Unwrap() are followed, so errors wrapped with fmt.Errorf("%w") are also supported.
+// It returns nil if the error doesn't have a cause.
 func ErrCause(err error) error {
-	if e, ok := err.(errCauser); ok {
-		return e.Cause()
+	var cause error
+	for err != nil {
+		switch e := err.(type) {
+		case errCauser:
+			err = e.Cause()
+		case errUnwrapper:
+			err = e.Unwrap()
+		default:
+			err = nil
+		}
+		if err != nil {
+			cause = err
+		}
 	}
-	return nil
+	return cause
+}
+
+// FromError returns the first error status found in the wrap chain of given error.
+// It returns false if the chain doesn't have an error status.
+func FromError(err error) (ErrServiceStatus, bool) {
+	var errSvc ErrServiceStatus
+	if errors.As(err, &errSvc) {
+		return errSvc, true
+	}
+	var errSvcP *ErrServiceStatus
+	if errors.As(err, &errSvcP) && errSvcP != nil {
+		return *errSvcP, true
+	}
+	return ErrServiceStatus{}, false
+}
+
+// Convert returns the first error status found in the wrap chain of given error.
+// Errors without an error status are converted to internal server error that wraps given error.
+func Convert(err error) ErrServiceStatus {
+	if errSvc, ok := FromError(err); ok {
+		return errSvc
+	}
+	return ErrInternal().WithError(err)
+}
+
+// CodeOf returns the code of the first error status found in the wrap chain of given error.
+// It returns codes.Success for nil error and codes.ErrInternal when there is no error status in the chain.
+func CodeOf(err error) codes.Code {
+	if err == nil {
+		return codes.Success
+	}
+	if errSvc, ok := FromError(err); ok {
+		return errSvc.Code
+	}
+	return codes.ErrInternal
 }
 
 // Success represents a generic success.
 func Success() ServiceStatus {
-	return ServiceStatus{Code: codes.Success, Message: "OK"}
+	return ServiceStatus{Code: codes.Success, Message: codes.Success.Message()}
 }
 
 // ServiceStatus captures basic information about a status construct.
 type ServiceStatus struct {
-	Code    codes.Code   `json:"code,omitempty"`
-	Message string       `json:"msg,omitempty"`
-	Details []*StatusDtl `json:"details,omitempty"`
+	Code    codes.Code   `json:"code,omitempty" xml:"code,omitempty"`
+	Message string       `json:"msg,omitempty" xml:"msg,omitempty"`
+	Details []*StatusDtl `json:"details,omitempty" xml:"details>detail,omitempty"`
 }
 
 // StatusDtl captures basic information about a status construct.
+// Field is the path of the request field the detail is about, for example 'address.postcode' or 'items[0].sku'.
+// For validation errors Code is the machine-readable rule code and Params are the rule parameters, for example {"min": 1}.
 type StatusDtl struct {
-	Code    string `json:"code,omitempty"`
-	Message string `json:"msg,omitempty"`
+	Field   string                 `json:"field,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Message string                 `json:"msg,omitempty"`
+	Params  map[string]interface{} `json:"params,omitempty"`
 }
 
 // ErrServiceStatus captures basic information about an error.
+// Message and Details are client safe and are the only parts rendered to clients.
+// Internal message and the wrapped error are kept apart for diagnostics, see LogFields.
 type ErrServiceStatus struct {
 	ServiceStatus
-	err error
+	internal string
+	meta     map[string]string
+	err      error
+	stack    stack
 }
 
-// WithMessage returns an error status with given message.
+// WithMessage returns an error status with given client safe message.
 func (e ErrServiceStatus) WithMessage(msg string) ErrServiceStatus {
-	var err ErrServiceStatus
-	copier.Copy(&err, e)
-	err.Message = strings.Join([]string{e.Message, msg}, ": ")
-	return err
+	ex := e.clone()
+	ex.Message = strings.Join([]string{e.Message, msg}, ": ")
+	return ex
 }
 
-// WithError returns an error status with given err.Error().
+// WithInternalMessage returns an error status with given internal message.
+// Internal message is meant for diagnostics and is never rendered to clients.
+func (e ErrServiceStatus) WithInternalMessage(msg string) ErrServiceStatus {
+	ex := e.clone()
+	ex.internal = joinMsgs(e.internal, msg)
+	return ex
+}
+
+// WithError returns an error status that wraps given err.
+// Text of err is not added to the message as it may carry internal details like SQL or file paths.
+// Stack is captured here when it wasn't captured already at creation, see SetStackMode.
 func (e ErrServiceStatus) WithError(err error) ErrServiceStatus {
-	ex := e.WithMessage(err.Error())
+	ex := e.clone()
 	ex.err = err
+	if len(ex.stack) == 0 {
+		ex.stack = callers(1)
+	}
+	return ex
+}
+
+// WithDtl returns an error status with given details appended.
+func (e ErrServiceStatus) WithDtl(dd ...*StatusDtl) ErrServiceStatus {
+	ex := e.clone()
+	ex.Details = append(ex.Details, dd...)
+	return ex
+}
+
+// WithMeta returns an error status with given metadata, for example "Retry-After".
+// HTTP transport writes metadata as response headers.
+func (e ErrServiceStatus) WithMeta(key, value string) ErrServiceStatus {
+	ex := e.clone()
+	ex.meta = make(map[string]string, len(e.meta)+1)
+	for k, v := range e.meta {
+		ex.meta[k] = v
+	}
+	ex.meta[key] = value
+	return ex
+}
+
+// WithRetryAfter returns an error status with "Retry-After" metadata set to given duration in seconds.
+// Durations of zero or less are ignored and the error status is returned as is.
+func (e ErrServiceStatus) WithRetryAfter(d time.Duration) ErrServiceStatus {
+	if d <= 0 {
+		return e
+	}
+	secs := int64((d + time.Second - 1) / time.Second)
+	return e.WithMeta(MetaRetryAfter, strconv.FormatInt(secs, 10))
+}
+
+// Meta returns metadata of the error status.
+func (e ErrServiceStatus) Meta() map[string]string {
+	m := make(map[string]string, len(e.meta))
+	for k, v := range e.meta {
+		m[k] = v
+	}
+	return m
+}
+
+// RetryAfter returns the duration set with WithRetryAfter, it returns false if it is not set.
+func (e ErrServiceStatus) RetryAfter() (time.Duration, bool) {
+	secs, err := strconv.ParseInt(e.meta[MetaRetryAfter], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// InternalMessage returns the internal message of the error status.
+func (e ErrServiceStatus) InternalMessage() string {
+	return e.internal
+}
+
+// LogFields returns fields that describe the error status in full for structured logging.
+// Unlike rendered error status, it includes internal message and the chain of wrapped errors.
+// Message is keyed 'status_msg', as 'msg' is reserved by loggers like logrus for the log message.
+func (e ErrServiceStatus) LogFields() map[string]interface{} {
+	f := map[string]interface{}{
+		"code":       e.Code.String(),
+		"status_msg": e.Message,
+	}
+	if len(e.Details) > 0 {
+		f["details"] = e.Details
+	}
+	if e.internal != "" {
+		f["internal"] = e.internal
+	}
+	if len(e.meta) > 0 {
+		f["meta"] = e.meta
+	}
+	if e.err != nil {
+		f["cause"] = errText(e.err)
+	}
+	if len(e.stack) > 0 {
+		f["origin"] = e.Origin()
+		f["stack"] = e.stack.lines()
+	}
+	return f
+}
+
+func (e ErrServiceStatus) clone() ErrServiceStatus {
+	ex := e
+	ex.Details = append([]*StatusDtl(nil), e.Details...)
 	return ex
 }
 
+// errText returns error text of given error, for an error status it includes the internal message and its causes.
+func errText(err error) string {
+	errSvc, ok := err.(ErrServiceStatus)
+	if !ok {
+		return err.Error()
+	}
+	txt := joinMsgs(errSvc.Message, errSvc.internal)
+	if errSvc.err != nil {
+		txt = joinMsgs(txt, errText(errSvc.err))
+	}
+	return txt
+}
+
+func joinMsgs(msgs ...string) string {
+	var mm []string
+	for _, m := range msgs {
+		if m != "" {
+			mm = append(mm, m)
+		}
+	}
+	return strings.Join(mm, ": ")
+}
+
 // AddDtlMsg returns an error status with given message.
 func (e *ErrServiceStatus) AddDtlMsg(msgs ...string) {
 	for _, m := range msgs {
@@ -118,6 +352,12 @@ func (e *ErrServiceStatus) AddDtl(code, msg string) {
 	e.Details = append(e.Details, d)
 }
 
+// AddFieldDtl adds a detail about given request field.
+func (e *ErrServiceStatus) AddFieldDtl(field, code, msg string) {
+	d := &StatusDtl{Field: field, Code: code, Message: msg}
+	e.Details = append(e.Details, d)
+}
+
 // NewUserDefined returns a new status with given code and message.
 func NewUserDefined(code codes.Code, msg string) ServiceStatus {
 	return ServiceStatus{Code: code, Message: msg}
@@ -127,7 +367,7 @@ func (e ErrServiceStatus) Error() string {
 	if errB, err := json.Marshal(&e); err == nil {
 		return string(errB)
 	}
-	return `{"code":500, "msg": "error marshal failed"}`
+	return `{"code":"INTERNAL","msg":"error marshal failed"}`
 }
 
 // Cause returns an error causer.
@@ -135,10 +375,32 @@ func (e ErrServiceStatus) Cause() error {
 	return e.err
 }
 
+// Unwrap returns the wrapped error, allowing errors.Is and errors.As to look through the error status.
+func (e ErrServiceStatus) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is an error status with the same code.
+// It allows errors.Is(err, status.ErrNotFound()) to match an error status anywhere in the wrap chain of err.
+func (e ErrServiceStatus) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrServiceStatus:
+		return e.Code == t.Code
+	case *ErrServiceStatus:
+		return t != nil && e.Code == t.Code
+	}
+	return false
+}
+
+// IsCode reports whether the error status has given code.
+func (e ErrServiceStatus) IsCode(code codes.Code) bool {
+	return e.Code == code
+}
+
 type errCauser interface {
 	Cause() error
 }
 
-func (e ErrServiceStatus) Is(code codes.Code) bool {
-	return e.Code == code
+type errUnwrapper interface {
+	Unwrap() error
 }
@@ -0,0 +1,61 @@
+package valderr_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/valderr"
+)
+
+type address struct {
+	Postcode string `json:"postcode"`
+}
+
+func (a address) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Postcode, validation.Required, validation.Length(4, 6)),
+	)
+}
+
+type customer struct {
+	Name      string    `json:"name"`
+	Address   address   `json:"address"`
+	Locations []address `json:"locations"`
+}
+
+func (c customer) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.Address),
+		validation.Field(&c.Locations),
+	)
+}
+
+func ExampleNewErrStatusWithValErrors() {
+	c := customer{Address: address{Postcode: "200"}, Locations: []address{{"2000"}, {""}}}
+	err := c.Validate()
+
+	errSvc := valderr.NewErrStatusWithValErrors(status.ErrBadRequest(), err.(validation.Errors))
+	for _, d := range errSvc.Details {
+		b, _ := json.Marshal(d)
+		fmt.Println(string(b))
+	}
+	// Output:
+	// {"field":"address.postcode","code":"validation_length_out_of_range","msg":"the length must be between 4 and 6","params":{"max":6,"min":4}}
+	// {"field":"locations[1].postcode","code":"validation_required","msg":"cannot be blank"}
+	// {"field":"name","code":"validation_required","msg":"cannot be blank"}
+}
+
+func ExampleNewErrStatusWithValErrors_customMessage() {
+	// Rules with custom messages cannot be recognised, they get the generic code.
+	err := validation.Errors{"name": validation.Validate("", validation.Required.Error("name is mandatory"))}
+
+	errSvc := valderr.NewErrStatusWithValErrors(status.ErrBadRequest(), err)
+	b, _ := json.Marshal(errSvc.Details[0])
+	fmt.Println(string(b))
+	// Output:
+	// {"field":"name","code":"validation_invalid","msg":"name is mandatory"}
+}
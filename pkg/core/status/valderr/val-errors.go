@@ -1,17 +1,156 @@
 package valderr
 
 import (
+	"regexp"
+	"sort"
+	"strconv"
+
 	validation "github.com/go-ozzo/ozzo-validation"
-	"github.com/jinzhu/copier"
 
 	"github.com/govinda-attal/kiss-lib/pkg/core/status"
 )
 
+// Rule codes for the built-in rules of ozzo-validation.
+// They match the error codes of later ozzo-validation versions so that clients are not affected by an upgrade.
+const (
+	CodeRequired          = "validation_required"
+	CodeNotNilRequired    = "validation_not_nil_required"
+	CodeLengthTooLong     = "validation_length_too_long"
+	CodeLengthTooShort    = "validation_length_too_short"
+	CodeLengthInvalid     = "validation_length_invalid"
+	CodeLengthOutOfRange  = "validation_length_out_of_range"
+	CodeMinInvalid        = "validation_min_greater_equal_than_required"
+	CodeMaxInvalid        = "validation_max_less_equal_than_required"
+	CodeMatchInvalid      = "validation_match_invalid"
+	CodeInInvalid         = "validation_in_invalid"
+	CodeNotInInvalid      = "validation_not_in_invalid"
+	CodeDateInvalid       = "validation_date_invalid"
+	CodeMultipleOfInvalid = "validation_multiple_of_invalid"
+	// CodeInvalid is used when the rule of a validation error cannot be recognised.
+	CodeInvalid = "validation_invalid"
+)
+
+// codedErr is implemented by validation errors that carry their rule code, like ozzo-validation v4 ErrorObject.
+type codedErr interface {
+	Code() string
+}
+
+// paramsErr is implemented by validation errors that carry their rule parameters, like ozzo-validation v4 ErrorObject.
+type paramsErr interface {
+	Params() map[string]interface{}
+}
+
+type rule struct {
+	code   string
+	re     *regexp.Regexp
+	params []string
+}
+
+// rules recognise default English messages of ozzo-validation v3 built-in rules, which carry no codes of their own.
+// Matching is best-effort: a rule given a custom message with its Error method is not recognised and gets CodeInvalid.
+var rules = []rule{
+	{CodeRequired, regexp.MustCompile(`^cannot be blank$`), nil},
+	{CodeNotNilRequired, regexp.MustCompile(`^is required$`), nil},
+	{CodeLengthTooLong, regexp.MustCompile(`^the length must be no more than (\S+)$`), []string{"max"}},
+	{CodeLengthTooShort, regexp.MustCompile(`^the length must be no less than (\S+)$`), []string{"min"}},
+	{CodeLengthInvalid, regexp.MustCompile(`^the length must be exactly (\S+)$`), []string{"length"}},
+	{CodeLengthOutOfRange, regexp.MustCompile(`^the length must be between (\S+) and (\S+)$`), []string{"min", "max"}},
+	{CodeMinInvalid, regexp.MustCompile(`^must be (?:no less|greater) than (.+)$`), []string{"threshold"}},
+	{CodeMaxInvalid, regexp.MustCompile(`^must be (?:no greater|less) than (.+)$`), []string{"threshold"}},
+	{CodeMatchInvalid, regexp.MustCompile(`^must be in a valid format$`), nil},
+	{CodeInInvalid, regexp.MustCompile(`^must be a valid value$`), nil},
+	{CodeNotInInvalid, regexp.MustCompile(`^must not be in list$`), nil},
+	{CodeDateInvalid, regexp.MustCompile(`^must be a valid date$`), nil},
+	{CodeMultipleOfInvalid, regexp.MustCompile(`^must be multiple of (\S+)$`), []string{"base"}},
+}
+
+// NewErrStatusWithValErrors returns given error status with a detail for each validation error.
+// Nested validation errors of structs, maps and slices are walked recursively,
+// each detail carries the field path (for example 'address.postcode' or 'items[0].sku'), the rule code and its parameters.
+// Rule code and parameters are taken from the error when it carries them (as ozzo-validation v4 ErrorObject does),
+// otherwise they are recognised from the default message of the rule on a best-effort basis, falling back to CodeInvalid.
 func NewErrStatusWithValErrors(e status.ErrServiceStatus, valErrs validation.Errors) status.ErrServiceStatus {
-	var errSvc status.ErrServiceStatus
-	copier.Copy(&errSvc, e)
-	for _, msg := range valErrs {
-		errSvc.AddDtlMsg(msg.Error())
+	return e.WithDtl(valDtls("", valErrs)...)
+}
+
+func valDtls(path string, valErrs validation.Errors) []*status.StatusDtl {
+	var dd []*status.StatusDtl
+	for _, k := range sortedKeys(valErrs) {
+		err := valErrs[k]
+		if err == nil {
+			continue
+		}
+		field := fieldPath(path, k)
+		if nested, ok := err.(validation.Errors); ok {
+			dd = append(dd, valDtls(field, nested)...)
+			continue
+		}
+		d := &status.StatusDtl{Field: field, Code: CodeInvalid, Message: err.Error()}
+		if ce, ok := err.(codedErr); ok {
+			d.Code = ce.Code()
+		} else {
+			d.Code, d.Params = ruleOf(d.Message)
+		}
+		if pe, ok := err.(paramsErr); ok {
+			d.Params = pe.Params()
+		}
+		dd = append(dd, d)
+	}
+	return dd
+}
+
+func ruleOf(msg string) (string, map[string]interface{}) {
+	for _, r := range rules {
+		m := r.re.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		var params map[string]interface{}
+		for i, name := range r.params {
+			if params == nil {
+				params = make(map[string]interface{})
+			}
+			params[name] = paramVal(m[i+1])
+		}
+		return r.code, params
+	}
+	return CodeInvalid, nil
+}
+
+func paramVal(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+// fieldPath joins a key to the path, keys of slices are joined as indexes.
+func fieldPath(path, key string) string {
+	if _, err := strconv.Atoi(key); err == nil {
+		return path + "[" + key + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns keys in order, indexes of slices are ordered numerically.
+func sortedKeys(valErrs validation.Errors) []string {
+	kk := make([]string, 0, len(valErrs))
+	for k := range valErrs {
+		kk = append(kk, k)
 	}
-	return errSvc
+	sort.Slice(kk, func(i, j int) bool {
+		ni, erri := strconv.Atoi(kk[i])
+		nj, errj := strconv.Atoi(kk[j])
+		if erri == nil && errj == nil {
+			return ni < nj
+		}
+		return kk[i] < kk[j]
+	})
+	return kk
 }
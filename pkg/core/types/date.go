@@ -16,10 +16,24 @@ func (d *Date) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON unmarshals a slice of bytes with a date in format '2006-01-02' to Date.
 func (d *Date) UnmarshalJSON(b []byte) error {
-	v, err := time.Parse("2006-01-02", string(b[1:len(b)-1]))
+	if len(b) < 2 {
+		return fmt.Errorf("invalid date %s", string(b))
+	}
+	return d.UnmarshalText(b[1 : len(b)-1])
+}
+
+// UnmarshalText unmarshals text with a date in format '2006-01-02' to Date.
+// It allows Date to be used for query parameters, headers and form fields.
+func (d *Date) UnmarshalText(b []byte) error {
+	v, err := time.Parse("2006-01-02", string(b))
 	if err != nil {
 		return err
 	}
 	*d = Date(v)
 	return nil
 }
+
+// String returns the date in format '2006-01-02'.
+func (d Date) String() string {
+	return time.Time(d).Format("2006-01-02")
+}
@@ -5,19 +5,26 @@ import (
 	"strings"
 )
 
-// FileObj is custom type that represents a file data in memory. This type was defined to facilitate reading of file data passed within multipart/form-data.
+// FileObj is custom type that represents a file data, in memory or in a temporary file. This type was defined to facilitate reading of file data passed within multipart/form-data or a raw HTTP request body.
 // Once the file read from the http request within an instance of this type, it can be used within the application logic for relevant purpose.
 // Example can be to load file data into S3 or Google Cloud Storage.
 type FileObj struct {
-	name   string
-	size   int64
-	reader io.Reader
+	name        string
+	size        int64
+	reader      io.Reader
+	contentType string
+	sha256      string
 }
 
 // NewFileObj returns an instance of type FileObj which is simply aggregation of the input parameters.
 // Reader can be bytes.Reader to represent unstructured data in bytes.
 func NewFileObj(name string, size int64, reader io.Reader) *FileObj {
-	return &FileObj{name, size, reader}
+	return &FileObj{name: name, size: size, reader: reader}
+}
+
+// NewFileObjWithMeta returns an instance of type FileObj like NewFileObj, along with content type and hex encoded SHA-256 checksum of the file data.
+func NewFileObjWithMeta(name string, size int64, reader io.Reader, contentType, sha256 string) *FileObj {
+	return &FileObj{name: name, size: size, reader: reader, contentType: contentType, sha256: sha256}
 }
 
 // Name returns name of the file.
@@ -35,6 +42,25 @@ func (f FileObj) Reader() io.Reader {
 	return f.reader
 }
 
+// Close releases the file data, for example it removes the temporary file the data is held in.
+// It does nothing for file data in memory.
+func (f FileObj) Close() error {
+	if c, ok := f.reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// ContentType returns media type of the file if known.
+func (f FileObj) ContentType() string {
+	return f.contentType
+}
+
+// SHA256 returns hex encoded SHA-256 checksum of the file data if computed.
+func (f FileObj) SHA256() string {
+	return f.sha256
+}
+
 // Ext returns file extension from the file name if available.
 // There is no intention to verify or read the file data to return correct extension of the file.
 func (f FileObj) Ext() string {
@@ -49,7 +49,7 @@ func WithTx(ctx context.Context, pool *pgx.ConnPool, fn TxFunc, opts *pgx.TxOpti
 	}()
 	err = fn(ctx, tx)
 	if err != nil {
-		if _, ok := err.(status.ErrServiceStatus); !ok {
+		if _, ok := status.FromError(err); !ok {
 			return status.ErrInternal().WithError(err)
 		}
 	}
@@ -64,7 +64,7 @@ func WithTx(ctx context.Context, db *sql.DB, fn TxFunc, opts *sql.TxOptions) (er
 	}()
 	err = fn(ctx, tx)
 	if err != nil {
-		if _, ok := err.(status.ErrServiceStatus); !ok {
+		if _, ok := status.FromError(err); !ok {
 			return status.ErrInternal().WithError(err)
 		}
 	}
@@ -94,7 +94,7 @@ func WithTxx(ctx context.Context, db *sqlx.DB, fn TxxFunc, opts *sql.TxOptions)
 	}()
 	err = fn(ctx, txx)
 	if err != nil {
-		if _, ok := err.(status.ErrServiceStatus); !ok {
+		if _, ok := status.FromError(err); !ok {
 			return status.ErrInternal().WithError(err)
 		}
 	}
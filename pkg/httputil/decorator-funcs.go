@@ -8,6 +8,7 @@ import (
 	"github.com/dgrijalva/jwt-go"
 
 	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
 	"github.com/govinda-attal/kiss-lib/pkg/jwtkit"
 )
 
@@ -23,8 +24,10 @@ func AuthDecorator(v jwtkit.Verifier) DecoratorFunc {
 				ctx = context.WithValue(ctx, CtxKeyToken, token)
 				claims, err := v.VerifyToken(token)
 				if err != nil {
-					errSvc := err.(status.ErrServiceStatus)
-					return status.ErrUnauthorized().WithMessage(errSvc.Message)
+					if errSvc := status.Convert(err); errSvc.IsCode(codes.ErrUnauthorized) {
+						return errSvc
+					}
+					return status.ErrUnauthorized().WithError(err)
 				}
 				sub := claims.(jwt.MapClaims)["sub"]
 				ctx = context.WithValue(ctx, CtxKeyAuthSubj, sub)
@@ -14,5 +14,16 @@
 //
 // d) JSON & File Request Binder utility methods to simplify Multipart request methods.
 //
+// f) Error status returned by API handlers can be rendered as problem details (RFC 7807) with content type 'application/problem+json'.
+//
+// g) Path variables, query parameters, headers, cookies and urlencoded form fields can be bound to tagged struct fields with type conversion.
+// Several binders can be combined to populate one request struct in a single call, with bad fields of all sources reported in one error.
+//
+// h) Bound request data that implements ozzo-validation Validatable is validated, so API handlers only see valid input.
+//
+// i) Request binders and response renderers for XML, Protocol Buffers and MessagePack, besides JSON. Renderers are negotiated from the Accept header, and large results can be streamed as NDJSON or Server-Sent Events.
+//
+// j) Paging of list endpoints with limit, offset and signed cursors, rendered as a standard envelope along with Link headers (RFC 8288).
+//
 // NOTE: Within Golang, it is an anti-pattern to dump utility functions to utility based packages. It is rather advised to organise them as per their purpose.
 package httputil
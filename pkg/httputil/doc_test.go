@@ -1,15 +1,31 @@
 package httputil_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"math"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/govinda-attal/kiss-lib/core/status"
-	"github.com/govinda-attal/kiss-lib/core/types"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/gorilla/mux"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/types"
 	"github.com/govinda-attal/kiss-lib/pkg/httputil"
 	"github.com/govinda-attal/kiss-lib/pkg/jwtkit"
-	"github.com/gorilla/mux"
 )
 
 func ExampleNotFoundHandler_gorillaMux() {
@@ -33,7 +49,42 @@ func ExampleWrapperHandler() {
 		Methods("GET")
 }
 
-func ExampleAuthHandler() {
+func ExampleSetErrRend_problem() {
+	defer httputil.SetErrRends(httputil.ErrRends()...)
+	// Best done in main package at bootstrap.
+	httputil.SetErrRend(httputil.ProblemErrRend)
+
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		return status.ErrNotFound().WithMessage("customer")
+	}
+
+	rq := httptest.NewRequest("GET", "/customers/1", nil)
+	rq.Header.Set("X-Request-ID", "8d1c7bd2")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+
+	fmt.Println(rs.Code, rs.Header().Get("Content-Type"))
+	fmt.Print(rs.Body.String())
+	// Output:
+	// 404 application/problem+json
+	// {"type":"about:blank","title":"Not Found","status":404,"detail":"Not Found: customer","instance":"8d1c7bd2","code":"NOT_FOUND"}
+}
+
+func ExampleWrapperHandler_retryAfter() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		// Metadata of the error status is written as response headers.
+		return status.ErrTooManyRequests().WithRetryAfter(30 * time.Second)
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/quotes", nil))
+
+	fmt.Println(rs.Code, rs.Header().Get("Retry-After"))
+	// Output:
+	// 429 30
+}
+
+func ExampleAuthDecorator() {
 	// Verifier to verify authenticity of JWT bearer token.
 	v, err := jwtkit.NewRSAVerifier("path to public certificate file")
 
@@ -51,7 +102,7 @@ func ExampleAuthHandler() {
 
 	// HTTP POST calls to /hello/world is secured with Authorization header bearer token.
 	ex.HandleFunc("/world",
-		httputil.WrapperHandler(httputil.AuthHandler(handler, v))).
+		httputil.WrapperHandler(handler, httputil.AuthDecorator(v))).
 		Methods("POST")
 }
 
@@ -108,6 +159,686 @@ func ExampleRqBind_mpFormData() {
 	}
 }
 
+func ExampleMPStreamBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		dMap := map[string]interface{}{"title": ""}
+		fMap := map[string][]*types.FileObj{"pages": nil}
+		if err := httputil.RqBind(r, httputil.MPStreamBind(dMap, fMap, httputil.SpillBytes(4), httputil.MaxFileBytes(16))); err != nil {
+			return err
+		}
+		fmt.Println(dMap["title"])
+		for _, fo := range fMap["pages"] {
+			b, _ := ioutil.ReadAll(fo.Reader())
+			fmt.Println(fo.Name(), fo.Size(), string(b))
+		}
+		return nil
+	}
+
+	newRq := func(pages ...string) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField("title", "contract")
+		for i, p := range pages {
+			fw, _ := mw.CreateFormFile("pages", fmt.Sprintf("page-%d.txt", i+1))
+			io.WriteString(fw, p)
+		}
+		mw.Close()
+		rq := httptest.NewRequest("POST", "/documents", &body)
+		rq.Header.Set("Content-Type", mw.FormDataContentType())
+		return rq
+	}
+
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), newRq("abc", "spilled to disk"))
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, newRq("this page is far too long"))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// contract
+	// page-1.txt 3 abc
+	// page-2.txt 15 spilled to disk
+	// 413 {"code":"PAYLOAD_TOO_LARGE","msg":"Payload Too Large: part pages in multi-part message exceeds 16 bytes","details":[{"field":"pages","code":"bind_too_large","msg":"is too large","params":{"max_bytes":16}}]}
+}
+
+func ExampleFileBind_close() {
+	// Outside of WrapperHandler and with a context that is never done, temporary files are removed by closing the file.
+	rq := httptest.NewRequest("PUT", "/documents/1/file", strings.NewReader("%PDF-1.4 scanned contract"))
+	rq.Header.Set("Content-Type", "application/pdf")
+	var fo types.FileObj
+	if err := httputil.RqBind(rq, httputil.FileBind("scan", &fo).Opts(httputil.SpillBytes(8))); err != nil {
+		log.Fatal(err)
+	}
+	name := fo.Reader().(interface{ Name() string }).Name()
+	b, _ := ioutil.ReadAll(fo.Reader())
+	fmt.Println(string(b))
+
+	_, err := os.Stat(name)
+	fmt.Println(err == nil)
+	fo.Close()
+	_, err = os.Stat(name)
+	fmt.Println(os.IsNotExist(err))
+	// Output:
+	// %PDF-1.4 scanned contract
+	// true
+	// true
+}
+
+func ExampleFileBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var fo types.FileObj
+		if err := httputil.RqBind(r, httputil.FileBind("scan", &fo, "image/*", "application/pdf").Opts(httputil.MaxBytes(1<<20))); err != nil {
+			return err
+		}
+		fmt.Println(fo.Name(), fo.ContentType(), fo.Size(), fo.SHA256())
+		return nil
+	}
+
+	pdf := "%PDF-1.4 scanned contract"
+	rq := httptest.NewRequest("PUT", "/documents/1/file", strings.NewReader(pdf))
+	rq.Header.Set("Content-Type", "application/octet-stream")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rq = httptest.NewRequest("PUT", "/documents/1/file", strings.NewReader(pdf))
+	rq.Header.Set("Content-Type", "application/pdf")
+	rq.Header.Set("Content-Disposition", `attachment; filename*=UTF-8''contrat%20sign%C3%A9.pdf`)
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rq = httptest.NewRequest("PUT", "/documents/1/file", strings.NewReader(pdf))
+	rq.Header.Set("Content-Type", "image/png")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// scan.pdf application/pdf 25 057878db4b56758a7df0652ff71b99448915b8ac0e6d485ac2e6327b9bc7aacd
+	// contrat signé.pdf application/pdf 25 057878db4b56758a7df0652ff71b99448915b8ac0e6d485ac2e6327b9bc7aacd
+	// 415 {"code":"UNSUPPORTED_MEDIA_TYPE","msg":"Unsupported Media Type: content of the file is application/pdf, not image/png"}
+}
+
+func ExampleFileBind_disguised() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var fo types.FileObj
+		return httputil.RqBind(r, httputil.FileBind("upload", &fo, "image/png", "image/jpeg"))
+	}
+
+	// An executable declared as an image is rejected, as its content is not sniffed as one.
+	exe := "MZ\x90\x00\x03\x00\x00\x00\x04\x00\x00\x00\xff\xff\x00\x00PE\x00\x00"
+	rq := httptest.NewRequest("PUT", "/avatars/1", strings.NewReader(exe))
+	rq.Header.Set("Content-Type", "image/png")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// 415 {"code":"UNSUPPORTED_MEDIA_TYPE","msg":"Unsupported Media Type: content of the file is application/octet-stream, not image/png"}
+}
+
+func ExampleFormBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			Ref    string   `form:"ref,required"`
+			Amount float64  `form:"amount"`
+			Tags   []string `form:"tag"`
+		}
+		if err := httputil.RqBind(r, httputil.FormBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Println(rq.Ref, rq.Amount, rq.Tags)
+		return nil
+	}
+
+	rq := httptest.NewRequest("POST", "/callbacks", strings.NewReader("ref=TX-1&amount=12.5&tag=fx&tag=spot"))
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rq = httptest.NewRequest("POST", "/callbacks", strings.NewReader("amount=lots"))
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// TX-1 12.5 [fx spot]
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"ref","code":"validation_required","msg":"is required"},{"field":"amount","code":"bind_invalid_type","msg":"must be a number","params":{"type":"number"}}]}
+}
+
+func ExampleFormBind_map() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		m := map[string]interface{}{"amount": 0.0, "confirmed": false, "retries": (*int)(nil)}
+		if err := httputil.RqBind(r, httputil.FormBind(m)); err != nil {
+			return err
+		}
+		fmt.Println(m["ref"], m["amount"], m["confirmed"], m["tag"], *m["retries"].(*int))
+		return nil
+	}
+
+	rq := httptest.NewRequest("POST", "/callbacks", strings.NewReader("ref=TX-1&amount=12.5&confirmed=true&tag=fx&tag=spot&retries=3"))
+	rq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+	// Output:
+	// TX-1 12.5 true [fx spot] 3
+}
+
+func ExampleXMLBind() {
+	defer httputil.SetErrRends(httputil.ErrRends()...)
+	httputil.SetErrRend(httputil.XMLErrRend)
+
+	type payment struct {
+		XMLName  xml.Name `xml:"payment"`
+		Ref      string   `xml:"ref"`
+		Amount   float64  `xml:"amount"`
+		Currency string   `xml:"currency,attr"`
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq payment
+		if err := httputil.RqBind(r, httputil.XMLBind(&rq, httputil.MaxBytes(1<<10))); err != nil {
+			return err
+		}
+		return httputil.RsRender(w, httputil.XMLRend(&rq))
+	}
+
+	for _, body := range []string{
+		`<payment currency="AUD"><ref>TX-1</ref><amount>12.5</amount></payment>`,
+		`<payment currency="AUD"><ref>TX-1</ref><amount>lots</amount></payment>`,
+	} {
+		rq := httptest.NewRequest("POST", "/payments", strings.NewReader(body))
+		rq.Header.Set("Content-Type", "application/xml")
+		rs := httptest.NewRecorder()
+		httputil.WrapperHandler(handler)(rs, rq)
+		fmt.Println(rs.Code, rs.Body.String())
+	}
+	// Output:
+	// 200 <?xml version="1.0" encoding="UTF-8"?>
+	// <payment currency="AUD"><ref>TX-1</ref><amount>12.5</amount></payment>
+	// 400 <?xml version="1.0" encoding="UTF-8"?>
+	// <status><code>BAD_REQUEST</code><msg>Bad Request: malformed XML in the http request</msg><details><detail><code>bind_invalid_type</code><msg>value &#34;lots&#34; is not a valid number</msg><params><param name="offset">60</param></params></detail></details></status>
+}
+
+func ExampleProtoBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var name wrappers.StringValue
+		if err := httputil.RqBind(r, httputil.ProtoBind(&name, httputil.MaxBytes(1<<10))); err != nil {
+			return err
+		}
+		return httputil.RsRender(w, httputil.ProtoRend(&wrappers.StringValue{Value: "Hello " + name.Value}))
+	}
+
+	b, _ := proto.Marshal(&wrappers.StringValue{Value: "John"})
+	rq := httptest.NewRequest("POST", "/greetings", bytes.NewReader(b))
+	rq.Header.Set("Content-Type", "application/x-protobuf")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+
+	var greeting wrappers.StringValue
+	proto.Unmarshal(rs.Body.Bytes(), &greeting)
+	fmt.Println(rs.Code, rs.Header().Get("Content-Type"), greeting.Value)
+	// Output:
+	// 200 application/x-protobuf Hello John
+}
+
+func ExampleParamBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			ID    int        `path:"id"`
+			Since types.Date `query:"since"`
+			Tags  []string   `query:"tag"`
+			Limit *int       `query:"limit"`
+		}
+		if err := httputil.RqBind(r, httputil.ParamBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Println(rq.ID, rq.Since, rq.Tags, *rq.Limit)
+		return nil
+	}
+	r := mux.NewRouter()
+	r.HandleFunc("/customers/{id}/orders", httputil.WrapperHandler(handler)).Methods("GET")
+
+	rs := httptest.NewRecorder()
+	r.ServeHTTP(rs, httptest.NewRequest("GET", "/customers/42/orders?since=2019-03-01&tag=new,gift&limit=10", nil))
+
+	rs = httptest.NewRecorder()
+	r.ServeHTTP(rs, httptest.NewRequest("GET", "/customers/x/orders?limit=ten", nil))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// 42 2019-03-01 [new gift] 10
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"id","code":"bind_invalid_type","msg":"must be an integer","params":{"type":"integer"}},{"field":"limit","code":"bind_invalid_type","msg":"must be an integer","params":{"type":"integer"}}]}
+}
+
+func ExampleHdrBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			TenantID   string `header:"X-Tenant-ID,required"`
+			Locale     string `header:"Accept-Language" default:"en"`
+			APIVersion int    `header:"X-API-Version" default:"1"`
+			Session    string `cookie:"session"`
+		}
+		if err := httputil.RqBind(r, httputil.HdrBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Println(rq.TenantID, rq.Locale, rq.APIVersion, rq.Session)
+		return nil
+	}
+
+	rq := httptest.NewRequest("GET", "/quotes", nil)
+	rq.Header.Set("X-Tenant-ID", "acme")
+	rq.AddCookie(&http.Cookie{Name: "session", Value: "c2Vzc2lvbg"})
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/quotes", nil))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// acme en 1 c2Vzc2lvbg
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"X-Tenant-ID","code":"validation_required","msg":"is required"}]}
+}
+
+func ExampleStructBind() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			TenantID string  `header:"X-Tenant-ID,required"`
+			DryRun   bool    `query:"dryRun"`
+			Name     string  `json:"name"`
+			Amount   float64 `json:"amount"`
+		}
+		if err := httputil.RqBind(r, httputil.StructBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Println(rq.TenantID, rq.DryRun, rq.Name, rq.Amount)
+		return nil
+	}
+
+	rq := httptest.NewRequest("POST", "/quotes?dryRun=true", strings.NewReader(`{"name":"fx","amount":12.5}`))
+	rq.Header.Set("Content-Type", "application/json")
+	rq.Header.Set("X-Tenant-ID", "acme")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("POST", "/quotes?dryRun=maybe", nil))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// acme true fx 12.5
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"X-Tenant-ID","code":"validation_required","msg":"is required"},{"field":"dryRun","code":"bind_invalid_type","msg":"must be a boolean","params":{"type":"boolean"}}]}
+}
+
+func ExampleStructBind_headerFromBody() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			UserID string `header:"X-User-ID"`
+			Name   string `json:"name"`
+		}
+		if err := httputil.RqBind(r, httputil.StructBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Printf("%q %q\n", rq.UserID, rq.Name)
+		return nil
+	}
+
+	// Values set by gateways in headers can't be overridden with the body.
+	rq := httptest.NewRequest("POST", "/quotes", strings.NewReader(`{"UserID":"admin","name":"fx"}`))
+	rq.Header.Set("Content-Type", "application/json")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rq = httptest.NewRequest("POST", "/quotes", strings.NewReader(`{"UserID":"admin","name":"fx"}`))
+	rq.Header.Set("Content-Type", "application/json")
+	rq.Header.Set("X-User-ID", "u-42")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	// Body of unknown length, as with HTTP/2 requests without Content-Length.
+	rq = httptest.NewRequest("POST", "/quotes", ioutil.NopCloser(strings.NewReader(`{"name":"fy"}`)))
+	rq.ContentLength = -1
+	rq.Header.Set("Content-Type", "application/json")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+	// Output:
+	// "" "fx"
+	// "u-42" "fx"
+	// "" "fy"
+}
+
+func ExampleJSONBind_options() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq struct {
+			Name   string  `json:"name"`
+			Amount float64 `json:"amount"`
+		}
+		b := httputil.JSONBind(&rq, httputil.MaxBytes(64), httputil.DisallowUnknownFields(), httputil.RejectTrailingData())
+		return httputil.RqBind(r, b)
+	}
+
+	for _, body := range []string{
+		`{"name":"fx","amount":"12.5"}`,
+		`{"name":"fx","amt":12.5}`,
+		`{"name":"fx"} {"name":"fy"}`,
+		`{"name":"fx"} garbage`,
+		`{"name":"` + strings.Repeat("x", 64) + `"}`,
+	} {
+		rq := httptest.NewRequest("POST", "/quotes", strings.NewReader(body))
+		rq.Header.Set("Content-Type", "application/json")
+		rs := httptest.NewRecorder()
+		httputil.WrapperHandler(handler)(rs, rq)
+		fmt.Print(rs.Code, " ", rs.Body.String())
+	}
+	// Output:
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: malformed JSON in the http request","details":[{"field":"amount","code":"bind_invalid_type","msg":"must be a number","params":{"offset":28,"type":"number"}}]}
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: malformed JSON in the http request","details":[{"field":"amt","code":"bind_unknown_field","msg":"is not allowed"}]}
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: malformed JSON in the http request","details":[{"code":"bind_trailing_data","msg":"unexpected data after the JSON value","params":{"offset":13}}]}
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: malformed JSON in the http request","details":[{"code":"bind_trailing_data","msg":"unexpected data after the JSON value","params":{"offset":13}}]}
+	// 413 {"code":"PAYLOAD_TOO_LARGE","msg":"Payload Too Large: http request body exceeds 64 bytes"}
+}
+
+type quoteRq struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+func (q quoteRq) Validate() error {
+	return validation.ValidateStruct(&q,
+		validation.Field(&q.Currency, validation.Required, validation.Length(3, 3)),
+		validation.Field(&q.Amount, validation.Min(0.01)),
+	)
+}
+
+func ExampleRqBind_validation() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var rq quoteRq
+		if err := httputil.RqBind(r, httputil.JSONBind(&rq)); err != nil {
+			return err
+		}
+		fmt.Println(rq.Currency, rq.Amount)
+		return nil
+	}
+
+	rq := httptest.NewRequest("POST", "/quotes", strings.NewReader(`{"currency":"AUD","amount":12.5}`))
+	rq.Header.Set("Content-Type", "application/json")
+	httputil.WrapperHandler(handler)(httptest.NewRecorder(), rq)
+
+	rq = httptest.NewRequest("POST", "/quotes", strings.NewReader(`{"currency":"AU","amount":-1}`))
+	rq.Header.Set("Content-Type", "application/json")
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, rq)
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// AUD 12.5
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"amount","code":"validation_min_greater_equal_than_required","msg":"must be no less than 0.01","params":{"threshold":0.01}},{"field":"currency","code":"validation_length_invalid","msg":"the length must be exactly 3","params":{"length":3}}]}
+}
+
+func ExampleRsNegotiate() {
+	defer httputil.SetErrRends(httputil.ErrRends()...)
+	httputil.SetErrRends(httputil.JSONErrRend, httputil.XMLErrRend)
+
+	type greeting struct {
+		XMLName xml.Name `json:"-" xml:"greeting"`
+		Msg     string   `json:"msg" xml:"msg"`
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Query().Get("name") == "" {
+			return status.ErrBadRequest().WithMessage("name is required")
+		}
+		rs := greeting{Msg: "Hello " + r.URL.Query().Get("name")}
+		return httputil.RsNegotiate(w, r, &rs, httputil.JSONRend, httputil.XMLRend)
+	}
+
+	for _, tc := range []struct{ url, accept string }{
+		{"/hello?name=John", ""},
+		{"/hello?name=John", "application/json;q=0.5, application/xml"},
+		{"/hello?name=John", "text/csv"},
+		{"/hello", "text/*;q=0.2, application/xml;q=0.9"},
+	} {
+		rq := httptest.NewRequest("GET", tc.url, nil)
+		rq.Header.Set("Accept", tc.accept)
+		rs := httptest.NewRecorder()
+		httputil.WrapperHandler(handler)(rs, rq)
+		fmt.Print(rs.Code, " ", strings.TrimPrefix(rs.Body.String(), xml.Header))
+		if !strings.HasSuffix(rs.Body.String(), "\n") {
+			fmt.Println()
+		}
+	}
+	// Output:
+	// 200 {"msg":"Hello John"}
+	// 200 <greeting><msg>Hello John</msg></greeting>
+	// 406 {"code":"NOT_ACCEPTABLE","msg":"Not Acceptable: available content types are application/json, application/xml"}
+	// 400 <status><code>BAD_REQUEST</code><msg>Bad Request: name is required</msg></status>
+}
+
+func ExampleNDJSONRend() {
+	type row struct {
+		ID     int     `json:"id"`
+		Amount float64 `json:"amount"`
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		ch := make(chan interface{})
+		go func() {
+			defer close(ch)
+			for i := 1; i <= 3; i++ {
+				select {
+				case ch <- row{i, float64(i) * 10}:
+				case <-r.Context().Done():
+					return
+				}
+			}
+			ch <- status.ErrServiceUnavailable().WithMessage("report store")
+		}()
+		return httputil.RsRender(w, httputil.NDJSONRend(r.Context(), httputil.ChanIter(ch)))
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/reports/payments", nil))
+	fmt.Println(rs.Code, rs.Header().Get("Content-Type"))
+	fmt.Print(rs.Body.String())
+	// Output:
+	// 200 application/x-ndjson
+	// {"id":1,"amount":10}
+	// {"id":2,"amount":20}
+	// {"id":3,"amount":30}
+	// {"error":{"code":"SERVICE_UNAVAILABLE","msg":"Service Unavailable: report store"}}
+}
+
+func ExampleNDJSONRend_encodingFailure() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		ch := make(chan interface{}, 3)
+		ch <- map[string]float64{"rate": 0.65}
+		ch <- map[string]float64{"rate": math.NaN()}
+		ch <- map[string]float64{"rate": 0.66}
+		close(ch)
+		return httputil.RsRender(w, httputil.NDJSONRend(r.Context(), httputil.ChanIter(ch)))
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/rates", nil))
+	fmt.Print(rs.Body.String())
+	// Output:
+	// {"rate":0.65}
+	// {"error":{"code":"INTERNAL","msg":"Internal Server Error"}}
+}
+
+func ExampleSSERend() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		ch := make(chan interface{}, 3)
+		ch <- httputil.SSEEvent{ID: "41", Event: "price", Data: map[string]float64{"AUDUSD": 0.65}, Retry: 3 * time.Second}
+		ch <- "heartbeat"
+		ch <- errors.New("feed disconnected")
+		close(ch)
+		return httputil.RsRender(w, httputil.SSERend(r.Context(), httputil.ChanIter(ch)))
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/prices", nil))
+	fmt.Println(rs.Header().Get("Content-Type"), rs.Header().Get("Cache-Control"))
+	fmt.Print(rs.Body.String())
+	// Output:
+	// text/event-stream no-cache
+	// id: 41
+	// event: price
+	// retry: 3000
+	// data: {"AUDUSD":0.65}
+	//
+	// id: 1
+	// data: heartbeat
+	//
+	// event: error
+	// data: {"code":"INTERNAL","msg":"Internal Server Error"}
+	//
+}
+
+func ExampleSSERend_withStatus() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		ch := make(chan interface{}, 2)
+		ch <- httputil.SSEEvent{ID: "7\nevent: admin", Event: "price", Data: "line 1\rdata: line 2"}
+		ch <- math.Inf(1)
+		close(ch)
+		return httputil.RsRenderWithStatus(w, httputil.SSERend(r.Context(), httputil.ChanIter(ch)), http.StatusOK)
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/prices", nil))
+	// Headers as written with the status.
+	hdr := rs.Result().Header
+	fmt.Println(hdr.Get("Cache-Control"), hdr.Get("X-Accel-Buffering"))
+	fmt.Print(rs.Body.String())
+	// Output:
+	// no-cache no
+	// id: 7event: admin
+	// event: price
+	// data: line 1
+	// data: data: line 2
+	//
+	// event: error
+	// data: {"code":"INTERNAL","msg":"Internal Server Error"}
+	//
+}
+
+func ExampleFileRend() {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		data := []byte("%PDF-1.4 signed contract")
+		fo := types.NewFileObjWithMeta("contrat signé.pdf", int64(len(data)), bytes.NewReader(data), "", "3f2a")
+		return httputil.RsRender(w, httputil.FileRend(r, fo, httputil.FileAttachment()))
+	}
+
+	for _, hdr := range [][2]string{{}, {"Range", "bytes=0-7"}, {"If-None-Match", `"3f2a"`}} {
+		rq := httptest.NewRequest("GET", "/documents/1/file", nil)
+		if hdr[0] != "" {
+			rq.Header.Set(hdr[0], hdr[1])
+		}
+		rs := httptest.NewRecorder()
+		httputil.WrapperHandler(handler)(rs, rq)
+		fmt.Printf("%d %q %q %s %q\n", rs.Code, rs.Header().Get("Content-Type"), rs.Header().Get("Content-Length"), rs.Header().Get("ETag"), rs.Body.String())
+	}
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/documents/1/file", nil))
+	fmt.Println(rs.Header().Get("Content-Disposition"))
+	// Output:
+	// 200 "application/pdf" "24" "3f2a" "%PDF-1.4 signed contract"
+	// 206 "application/pdf" "8" "3f2a" "%PDF-1.4"
+	// 304 "" "" "3f2a" ""
+	// attachment; filename="contrat sign_.pdf"; filename*=UTF-8''contrat%20sign%C3%A9.pdf
+}
+
+func ExampleRsRenderCond() {
+	customer := struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}{1, "John Doe"}
+	lastMod := time.Date(2020, 5, 26, 10, 0, 0, 0, time.UTC)
+
+	get := func(w http.ResponseWriter, r *http.Request) error {
+		return httputil.RsRenderCond(w, r, httputil.JSONRend(&customer), httputil.CondLastModified(lastMod))
+	}
+	put := func(w http.ResponseWriter, r *http.Request) error {
+		etag, err := httputil.ETagOf(httputil.JSONRend(&customer))
+		if err != nil {
+			return err
+		}
+		if err := httputil.CheckIfMatch(r, etag); err != nil {
+			return err
+		}
+		// Update the customer.
+		return nil
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(get)(rs, httptest.NewRequest("GET", "/customers/1", nil))
+	etag := rs.Header().Get("ETag")
+	fmt.Println(rs.Code, rs.Header().Get("Last-Modified"), rs.Body.String() != "")
+
+	rq := httptest.NewRequest("GET", "/customers/1", nil)
+	rq.Header.Set("If-None-Match", etag)
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(get)(rs, rq)
+	fmt.Println(rs.Code, rs.Body.Len())
+
+	rq = httptest.NewRequest("PUT", "/customers/1", nil)
+	rq.Header.Set("If-Match", etag)
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(put)(rs, rq)
+	fmt.Println(rs.Code)
+
+	rq = httptest.NewRequest("PUT", "/customers/1", nil)
+	rq.Header.Set("If-Match", `"stale"`)
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(put)(rs, rq)
+	fmt.Print(rs.Code, " ", rs.Header().Get("ETag") == etag, " ", rs.Body.String())
+	// Output:
+	// 200 Tue, 26 May 2020 10:00:00 GMT true
+	// 304 0
+	// 200
+	// 412 true {"code":"PRECONDITION_FAILED","msg":"Precondition Failed: resource has changed since it was read"}
+}
+
+func ExampleRsPage() {
+	pager := httputil.NewPager([]byte("cursor-secret"), 2, 100)
+	ids := []int{1, 2, 3, 4, 5}
+
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		pr, err := pager.Bind(r)
+		if err != nil {
+			return err
+		}
+		var after int
+		if err := pr.DecodeCursor(&after); err != nil {
+			return err
+		}
+		var items []int
+		for _, id := range ids {
+			if id > after && len(items) < pr.Limit {
+				items = append(items, id)
+			}
+		}
+		page := httputil.Page{Items: items}
+		if len(items) > 0 {
+			if last := items[len(items)-1]; last < ids[len(ids)-1] {
+				if page.NextCursor, err = pager.Cursor(r, last); err != nil {
+					return err
+				}
+			}
+		}
+		return httputil.RsPage(w, r, pr, &page)
+	}
+
+	rs := httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/payments?status=settled", nil))
+	fmt.Println(rs.Header().Get("Link"))
+	fmt.Print(rs.Body.String())
+
+	var page struct {
+		NextCursor string `json:"next_cursor"`
+	}
+	json.NewDecoder(rs.Body).Decode(&page)
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/payments?status=settled&limit=500&cursor="+page.NextCursor, nil))
+	fmt.Print(rs.Body.String())
+
+	// Cursors are bound to filters of the request they were issued for.
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/payments?status=failed&cursor="+page.NextCursor, nil))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+
+	rs = httptest.NewRecorder()
+	httputil.WrapperHandler(handler)(rs, httptest.NewRequest("GET", "/payments?cursor=Zm9yZ2Vk&offset=-1", nil))
+	fmt.Print(rs.Code, " ", rs.Body.String())
+	// Output:
+	// </payments?limit=2&status=settled>; rel="first", </payments?cursor=naQwowBcjIGVGFGC6FcTGjI&limit=2&status=settled>; rel="next"
+	// {"items":[1,2],"next_cursor":"naQwowBcjIGVGFGC6FcTGjI"}
+	// {"items":[3,4,5]}
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"cursor","code":"paging_invalid_cursor","msg":"is not valid"}]}
+	// 400 {"code":"BAD_REQUEST","msg":"Bad Request: invalid values in the http request","details":[{"field":"offset","code":"validation_min_greater_equal_than_required","msg":"must be no less than 0","params":{"threshold":0}},{"field":"offset","code":"validation_invalid","msg":"cannot be used with cursor"},{"field":"cursor","code":"paging_invalid_cursor","msg":"is not valid"}]}
+}
+
 func ExampleRsRender_json() {
 
 	_ = func(w http.ResponseWriter, r *http.Request) error {
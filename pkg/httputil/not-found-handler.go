@@ -8,9 +8,8 @@ import (
 
 // NotFoundHandler is a custom NOT Found handler for gorilla mux.
 // It returns HTTP 404 Status along with custom JSON message - {msg: "Not Found: Resource path not mapped"}.
+// Message is rendered with the error renderer set by SetErrRend, or negotiated among those set by SetErrRends.
 func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotFound)
 	err := status.ErrNotFound().WithMessage("Resource path not mapped")
-	RsRender(w, JSONRend(&err))
+	RsRenderWithStatus(w, errRend(r, err), http.StatusNotFound)
 }
@@ -0,0 +1,183 @@
+package httputil
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/valderr"
+)
+
+// PagingCodeInvalidCursor is the detail code for a paging cursor that was not issued by the service or was tampered with.
+const PagingCodeInvalidCursor = "paging_invalid_cursor"
+
+// cursorMACLen is the length of the signature carried by a cursor.
+const cursorMACLen = 16
+
+// Pager binds paging requests from query parameters 'limit', 'cursor' and 'offset', issues opaque signed cursors,
+// and renders pages of list endpoints with RFC 8288 Link headers.
+type Pager struct {
+	secret   []byte
+	defLimit int
+	maxLimit int
+}
+
+// NewPager returns a pager that signs cursors with 'secret' (HMAC-SHA256).
+// Limit is 'defLimit' when not requested and is capped at 'maxLimit'.
+// It panics if the secret is empty, hence it is best called at bootstrap.
+func NewPager(secret []byte, defLimit, maxLimit int) *Pager {
+	if len(secret) == 0 {
+		panic("httputil: secret of the pager is required")
+	}
+	return &Pager{secret: secret, defLimit: defLimit, maxLimit: maxLimit}
+}
+
+// PageRq is a paging request. Either Cursor or Offset is used, a request can't have both.
+type PageRq struct {
+	Limit  int
+	Offset int
+	Cursor string
+	data   []byte
+}
+
+// DecodeCursor populates 'v' with the position the cursor of the request was issued for, see Pager.Cursor.
+// It leaves 'v' as is when the request has no cursor.
+func (pr PageRq) DecodeCursor(v interface{}) error {
+	if pr.data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(pr.data, v); err != nil {
+		return status.ErrBadRequest().WithError(err).
+			WithDtl(&status.StatusDtl{Field: "cursor", Code: PagingCodeInvalidCursor, Message: "is not valid"})
+	}
+	return nil
+}
+
+// Page is the envelope of a page of items. NextCursor is set when more items follow, Total when it is known.
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Total      *int64      `json:"total,omitempty"`
+}
+
+// Bind returns the paging request from query parameters of the HTTP request, verifying its cursor
+// and that the other query parameters, like filters and sort order, are those the cursor was issued for.
+// A missing or non positive limit is the default limit, a larger one than the maximum is capped.
+// Invalid values are reported as bad request with a detail for each parameter.
+func (p *Pager) Bind(r *http.Request) (PageRq, error) {
+	var q struct {
+		Limit  int    `query:"limit"`
+		Offset int    `query:"offset"`
+		Cursor string `query:"cursor"`
+	}
+	dd, err := bindTags(&q, tagSrc{"query", querySrc(r)})
+	if err != nil {
+		return PageRq{}, err
+	}
+	pr := PageRq{Limit: q.Limit, Offset: q.Offset, Cursor: q.Cursor}
+	if pr.Limit <= 0 {
+		pr.Limit = p.defLimit
+	}
+	if p.maxLimit > 0 && pr.Limit > p.maxLimit {
+		pr.Limit = p.maxLimit
+	}
+	if pr.Offset < 0 {
+		dd = append(dd, &status.StatusDtl{Field: "offset", Code: valderr.CodeMinInvalid, Message: "must be no less than 0", Params: map[string]interface{}{"threshold": 0}})
+	}
+	if pr.Cursor != "" {
+		if pr.Offset != 0 {
+			dd = append(dd, &status.StatusDtl{Field: "offset", Code: valderr.CodeInvalid, Message: "cannot be used with cursor"})
+		}
+		if pr.data, err = p.verify(r, pr.Cursor); err != nil {
+			dd = append(dd, &status.StatusDtl{Field: "cursor", Code: PagingCodeInvalidCursor, Message: "is not valid"})
+		}
+	}
+	if err := errBadRequestDtls(dd); err != nil {
+		return PageRq{}, err
+	}
+	return pr, nil
+}
+
+// Cursor returns an opaque cursor for position 'v', for example the sort key of the last item of a page.
+// The position is encoded as JSON and signed along with query parameters of the HTTP request other than paging ones,
+// so that clients can't forge cursors nor use them with other filters or sort order.
+func (p *Pager) Cursor(r *http.Request, v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", status.ErrInternal().WithInternalMessage("encoding paging cursor").WithError(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(append(p.sign(r, data), data...)), nil
+}
+
+// sign returns the signature of cursor 'data' for the query of the HTTP request, without paging parameters.
+func (p *Pager) sign(r *http.Request, data []byte) []byte {
+	q := r.URL.Query()
+	q.Del("cursor")
+	q.Del("offset")
+	q.Del("limit")
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(q.Encode()))
+	mac.Write([]byte{0})
+	mac.Write(data)
+	return mac.Sum(nil)[:cursorMACLen]
+}
+
+func (p *Pager) verify(r *http.Request, cursor string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < cursorMACLen {
+		return nil, fmt.Errorf("paging cursor is too short")
+	}
+	sig, data := b[:cursorMACLen], b[cursorMACLen:]
+	if !hmac.Equal(sig, p.sign(r, data)) {
+		return nil, fmt.Errorf("paging cursor signature mismatch")
+	}
+	return data, nil
+}
+
+// RsPage populates http response body with the page envelope as JSON, along with Link headers (RFC 8288)
+// to the first, previous and next pages as per the paging request 'pr' and the page.
+// Next page is linked with the next cursor of the page, or with the next offset when the request used offsets and the total is known.
+func RsPage(w http.ResponseWriter, r *http.Request, pr PageRq, page *Page) error {
+	w.Header().Set("Link", pageLinks(r.URL, pr, page))
+	return RsRender(w, JSONRend(page))
+}
+
+func pageLinks(u *url.URL, pr PageRq, page *Page) string {
+	link := func(rel string, set map[string]string) string {
+		q := u.Query()
+		q.Del("cursor")
+		q.Del("offset")
+		q.Set("limit", strconv.Itoa(pr.Limit))
+		for k, v := range set {
+			q.Set(k, v)
+		}
+		lu := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", lu.String(), rel)
+	}
+
+	links := []string{link("first", nil)}
+	if pr.Cursor == "" && pr.Offset > 0 {
+		prev := pr.Offset - pr.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link("prev", map[string]string{"offset": strconv.Itoa(prev)}))
+	}
+	switch {
+	case page.NextCursor != "":
+		links = append(links, link("next", map[string]string{"cursor": page.NextCursor}))
+	case pr.Cursor == "" && page.Total != nil && int64(pr.Offset+pr.Limit) < *page.Total:
+		links = append(links, link("next", map[string]string{"offset": strconv.Itoa(pr.Offset + pr.Limit)}))
+	}
+	return strings.Join(links, ", ")
+}
@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/codec"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// CodecBind populates given variable 'd' from HTTP request body encoded with codec 'c', for Content-Type of the codec or its aliases.
+// Option MaxBytes restricts size of the body, other options are ignored.
+func CodecBind(c codec.Codec, d interface{}, oo ...BindOpt) Binder {
+	return codecBind{codec: c, data: d, opts: newBindOpts(oo)}
+}
+
+// ProtoBind populates given message 'm' from application/x-protobuf HTTP request body.
+func ProtoBind(m proto.Message, oo ...BindOpt) Binder {
+	return CodecBind(codec.Protobuf, m, oo...)
+}
+
+// MsgPackBind populates given variable 'd' from application/msgpack HTTP request body.
+func MsgPackBind(d interface{}, oo ...BindOpt) Binder {
+	return CodecBind(codec.MsgPack, d, oo...)
+}
+
+type codecBind struct {
+	codec codec.Codec
+	data  interface{}
+	opts  bindOpts
+}
+
+func (cb codecBind) ContentType() string {
+	return cb.codec.ContentType()
+}
+
+func (cb codecBind) ContentCompatible(contentType string) bool {
+	c, ok := codec.ByContentType(contentType)
+	return ok && c.ContentType() == cb.codec.ContentType()
+}
+
+func (cb codecBind) Bind(r *http.Request) error {
+	if err := cb.codec.Decode(cb.opts.body(r), cb.data); err != nil {
+		if errSvc, ok := errTooLarge(err); ok {
+			return errSvc
+		}
+		return status.ErrBadRequest().WithMessage("malformed content in the http request").WithError(err).
+			WithDtl(&status.StatusDtl{Code: BindCodeMalformed, Message: "cannot be decoded as " + cb.codec.ContentType()})
+	}
+	return nil
+}
+
+func (cb codecBind) Data() interface{} {
+	return cb.data
+}
@@ -0,0 +1,258 @@
+package httputil
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/valderr"
+	"github.com/govinda-attal/kiss-lib/pkg/core/types"
+)
+
+// BindCodeInvalidType is the detail code for a request value that cannot be converted to the type of its field.
+const BindCodeInvalidType = "bind_invalid_type"
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	dateType            = reflect.TypeOf(types.Date{})
+)
+
+// valSrc returns request values for given name and whether they are present in the request.
+type valSrc func(name string) ([]string, bool)
+
+// tagSrc maps struct fields with given tag to request values of a source.
+type tagSrc struct {
+	tag string
+	src valSrc
+}
+
+// convErr describes a request value that cannot be converted, its message is client safe.
+type convErr struct {
+	msg string
+	typ string
+}
+
+func (ce *convErr) Error() string {
+	return ce.msg
+}
+
+// bindTags populates fields of the struct pointed by 'd' with request values as per struct tags of given sources.
+// Sources are applied in order, hence values of a later source take precedence.
+// A field without values is set from its 'default' tag when it is zero, or reported when its tag has 'required' option.
+// Conversion failures are returned as details so that all bad fields are reported at once.
+func bindTags(d interface{}, tss ...tagSrc) ([]*status.StatusDtl, error) {
+	rv := reflect.ValueOf(d)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, status.ErrInternal().WithInternalMessage(fmt.Sprintf("binding requires a pointer to a struct, got %T", d))
+	}
+	return bindStruct(rv.Elem(), tss)
+}
+
+// bindTagsErr populates 'd' like bindTags and reports conversion failures as bad request.
+func bindTagsErr(d interface{}, tss ...tagSrc) error {
+	dd, err := bindTags(d, tss...)
+	if err != nil {
+		return err
+	}
+	return errBadRequestDtls(dd)
+}
+
+// errBadRequestDtls returns a bad request error status with given details, or nil when there are none.
+func errBadRequestDtls(dd []*status.StatusDtl) error {
+	if len(dd) == 0 {
+		return nil
+	}
+	return status.ErrBadRequest().WithMessage("invalid values in the http request").WithDtl(dd...)
+}
+
+func bindStruct(sv reflect.Value, tss []tagSrc) ([]*status.StatusDtl, error) {
+	var dd []*status.StatusDtl
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		fv := sv.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			ddx, err := bindStruct(fv, tss)
+			if err != nil {
+				return nil, err
+			}
+			dd = append(dd, ddx...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		var field string
+		var bound, required bool
+		for _, ts := range tss {
+			name, opts := parseTag(sf.Tag.Get(ts.tag))
+			if name == "" {
+				continue
+			}
+			if field == "" {
+				field = name
+			}
+			required = required || opts["required"]
+			vals, ok := ts.src(name)
+			if !ok || len(vals) == 0 {
+				continue
+			}
+			bound = true
+			if err := setVals(fv, vals); err != nil {
+				dtl, err := convDtl(sf.Name, name, err)
+				if err != nil {
+					return nil, err
+				}
+				dd = append(dd, dtl)
+			}
+		}
+		if field == "" || bound {
+			continue
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			if fv.IsZero() {
+				if err := setVals(fv, []string{def}); err != nil {
+					return nil, status.ErrInternal().WithInternalMessage(fmt.Sprintf("invalid default value of field %s", sf.Name)).WithError(err)
+				}
+			}
+			continue
+		}
+		if required {
+			dd = append(dd, &status.StatusDtl{Field: field, Code: valderr.CodeRequired, Message: "is required"})
+		}
+	}
+	return dd, nil
+}
+
+// convDtl returns the detail for a conversion failure of a request value, other failures are internal errors.
+func convDtl(goName, name string, err error) (*status.StatusDtl, error) {
+	ce, ok := err.(*convErr)
+	if !ok {
+		return nil, status.ErrInternal().WithInternalMessage(fmt.Sprintf("binding field %s", goName)).WithError(err)
+	}
+	return &status.StatusDtl{
+		Field:   name,
+		Code:    BindCodeInvalidType,
+		Message: ce.msg,
+		Params:  map[string]interface{}{"type": ce.typ},
+	}, nil
+}
+
+// parseTag returns the name and the options from a struct tag value like 'name,required'.
+func parseTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	opts := make(map[string]bool, len(parts)-1)
+	for _, o := range parts[1:] {
+		opts[strings.TrimSpace(o)] = true
+	}
+	return parts[0], opts
+}
+
+// setVals sets field 'v' with request values. Slices are populated from repeated or comma separated values.
+func setVals(v reflect.Value, vals []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 && !textUnmarshaler(v) {
+		var items []string
+		for _, val := range vals {
+			items = append(items, strings.Split(val, ",")...)
+		}
+		sv := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setVal(sv.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(sv)
+		return nil
+	}
+	return setVal(v, vals[0])
+}
+
+func textUnmarshaler(v reflect.Value) bool {
+	return reflect.PtrTo(v.Type()).Implements(textUnmarshalerType)
+}
+
+// setVal converts 's' to the type of field 'v' and sets it.
+func setVal(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		nv := reflect.New(v.Type().Elem())
+		if err := setVal(nv.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(nv)
+		return nil
+	}
+
+	switch v.Type() {
+	case dateType:
+		if err := v.Addr().Interface().(*types.Date).UnmarshalText([]byte(s)); err != nil {
+			return &convErr{"must be a date in format YYYY-MM-DD", "date"}
+		}
+		return nil
+	case timeType:
+		if err := v.Addr().Interface().(*time.Time).UnmarshalText([]byte(s)); err != nil {
+			return &convErr{"must be a date-time in RFC 3339 format", "date-time"}
+		}
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return &convErr{"must be a duration like 1h30m", "duration"}
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	if textUnmarshaler(v) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return &convErr{"is not valid", v.Type().Name()}
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return &convErr{"must be a boolean", "boolean"}
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return &convErr{"must be an integer", "integer"}
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return &convErr{"must be a non-negative integer", "integer"}
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return &convErr{"must be a number", "number"}
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
+
+// querySrc returns query parameters of the http request.
+func querySrc(r *http.Request) valSrc {
+	q := r.URL.Query()
+	return func(name string) ([]string, bool) {
+		vals, ok := q[name]
+		return vals, ok
+	}
+}
@@ -0,0 +1,348 @@
+package httputil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/types"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// fileExts are preferred file extensions for common content types, others are looked up with mime.ExtensionsByType.
+var fileExts = map[string]string{
+	"application/octet-stream": "bin",
+	"application/pdf":          "pdf",
+	"application/zip":          "zip",
+	"image/gif":                "gif",
+	"image/jpeg":               "jpg",
+	"image/png":                "png",
+	"image/webp":               "webp",
+	"text/plain":               "txt",
+}
+
+// FileBind populates given 'file' from the raw HTTP request body, for example a file uploaded with PUT.
+// Type of the file is sniffed from its data with http.DetectContentType, it must agree with Content-Type of the request
+// and must be one of the allowed content types 'contTypes', which may have wildcards like 'image/*'. All types are allowed when none is given.
+// Content-Type of a type that sniffing recognises, like images, PDF or zip, must be the sniffed type.
+// Generic Content-Type 'application/octet-stream' (or 'application/binary') leaves the type to the sniffed one.
+//
+// The file is named from Content-Disposition of the request, else from 'fname' with extension of the file type.
+// Size and SHA-256 checksum are computed while the body is read, and the file is spilled to a temporary file
+// beyond SpillBytes like MPStreamBind does, and removed alike. Options MaxBytes and SpillBytes can be set with Opts.
+func FileBind(fname string, file *types.FileObj, contTypes ...string) *fileBind {
+	fb := &fileBind{fname: fname, file: file, opts: bindOpts{spillBytes: DefaultSpillBytes}}
+	for _, ct := range contTypes {
+		fb.contTypes = append(fb.contTypes, mediaType(ct))
+	}
+	return fb
+}
+
+type fileBind struct {
+	fname     string
+	file      *types.FileObj
+	contTypes []string
+	opts      bindOpts
+}
+
+// Opts sets binding options MaxBytes and SpillBytes.
+func (fb *fileBind) Opts(oo ...BindOpt) *fileBind {
+	for _, o := range oo {
+		o(&fb.opts)
+	}
+	if fb.opts.spillBytes <= 0 {
+		fb.opts.spillBytes = DefaultSpillBytes
+	}
+	return fb
+}
+
+func (fb *fileBind) ContentType() string {
+	if len(fb.contTypes) == 0 || strings.HasSuffix(fb.contTypes[0], "*") {
+		return "application/octet-stream"
+	}
+	return fb.contTypes[0]
+}
+
+func (fb *fileBind) ContentCompatible(contentType string) bool {
+	ct := mediaType(contentType)
+	return ct == "" || ct == "application/octet-stream" || fb.allowed(ct)
+}
+
+func (fb *fileBind) Data() interface{} {
+	return fb.file
+}
+
+func (fb *fileBind) Bind(r *http.Request) error {
+	body := io.Reader(r.Body)
+	if fb.opts.maxBytes > 0 {
+		body = limitBody(r, fb.opts.maxBytes)
+	}
+	br := bufio.NewReaderSize(body, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return errReadFile(err)
+	}
+	if len(head) == 0 {
+		return status.ErrBadRequest().WithMessage("empty file in the http request")
+	}
+
+	declared := mediaType(r.Header.Get("Content-Type"))
+	sniffed := mediaType(http.DetectContentType(head))
+	if !sniffAgrees(declared, sniffed) {
+		return status.ErrContentTypeNotSupported().WithMessage(fmt.Sprintf("content of the file is %s, not %s", sniffed, declared))
+	}
+	// As the types agree, the type checked is the sniffed one when it is specific, unless declared type refines it, like docx does zip.
+	ct := declared
+	if ct == "" || ct == "application/octet-stream" {
+		ct = sniffed
+	}
+	if !fb.allowed(ct) {
+		return status.ErrContentTypeNotSupported().WithMessage(fmt.Sprintf("%s files are not accepted", ct))
+	}
+
+	h := sha256.New()
+	rd, size, err := bufferBody(r, io.TeeReader(br, h), fb.opts.spillBytes)
+	if err != nil {
+		return errReadFile(err)
+	}
+	fo := types.NewFileObjWithMeta(fb.fileName(r, ct), size, rd, ct, hex.EncodeToString(h.Sum(nil)))
+	if fb.file == nil {
+		fb.file = fo
+		return nil
+	}
+	*fb.file = *fo
+	return nil
+}
+
+// allowed returns true when content type 'ct' is in the allow-list.
+func (fb *fileBind) allowed(ct string) bool {
+	if len(fb.contTypes) == 0 {
+		return true
+	}
+	for _, a := range fb.contTypes {
+		if a == ct || a == "*/*" || (strings.HasSuffix(a, "/*") && strings.HasPrefix(ct, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileName returns the file name from Content-Disposition of the request, else 'fname' with extension of content type 'ct'.
+func (fb *fileBind) fileName(r *http.Request, ct string) string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+		name := path.Base(strings.Replace(params["filename"], "\\", "/", -1))
+		if name != "." && name != "/" && name != "" {
+			return name
+		}
+	}
+	name := fb.fname
+	if i := strings.LastIndex(name, "."); i > -1 {
+		name = name[:i]
+	}
+	if name == "" {
+		name = "file"
+	}
+	ext, ok := fileExts[ct]
+	if !ok {
+		if exts, _ := mime.ExtensionsByType(ct); len(exts) > 0 {
+			ext = strings.TrimPrefix(exts[0], ".")
+		}
+	}
+	if ext == "" {
+		return name
+	}
+	return name + "." + ext
+}
+
+func errReadFile(err error) error {
+	if errSvc, ok := status.FromError(err); ok {
+		return errSvc
+	}
+	if errSvc, ok := errTooLarge(err); ok {
+		return errSvc
+	}
+	return status.ErrBadRequest().WithMessage("error reading the http request").WithError(err)
+}
+
+// typeAliases map other names of content types to the names http.DetectContentType uses.
+var typeAliases = map[string]string{
+	"application/binary":           "application/octet-stream",
+	"application/gzip":             "application/x-gzip",
+	"application/vnd.rar":          "application/x-rar-compressed",
+	"application/x-zip-compressed": "application/zip",
+	"audio/mp3":                    "audio/mpeg",
+	"audio/wav":                    "audio/wave",
+	"audio/x-aiff":                 "audio/aiff",
+	"audio/x-midi":                 "audio/midi",
+	"audio/x-wav":                  "audio/wave",
+	"image/jpg":                    "image/jpeg",
+	"image/pjpeg":                  "image/jpeg",
+	"image/vnd.microsoft.icon":     "image/x-icon",
+	"video/x-msvideo":              "video/avi",
+}
+
+// sniffTypes are content types that http.DetectContentType recognises from signatures of the data.
+var sniffTypes = map[string]bool{
+	"application/ogg":               true,
+	"application/pdf":               true,
+	"application/postscript":        true,
+	"application/vnd.ms-fontobject": true,
+	"application/wasm":              true,
+	"application/x-gzip":            true,
+	"application/x-rar-compressed":  true,
+	"application/zip":               true,
+	"audio/aiff":                    true,
+	"audio/midi":                    true,
+	"audio/mpeg":                    true,
+	"audio/wave":                    true,
+	"font/collection":               true,
+	"font/otf":                      true,
+	"font/ttf":                      true,
+	"font/woff":                     true,
+	"font/woff2":                    true,
+	"image/bmp":                     true,
+	"image/gif":                     true,
+	"image/jpeg":                    true,
+	"image/png":                     true,
+	"image/webp":                    true,
+	"image/x-icon":                  true,
+	"video/avi":                     true,
+	"video/mp4":                     true,
+	"video/webm":                    true,
+}
+
+// mediaType returns the media type without parameters, with aliases like 'application/binary' taken as the name in typeAliases.
+func mediaType(contentType string) string {
+	ct := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	if a, ok := typeAliases[ct]; ok {
+		return a
+	}
+	return ct
+}
+
+// sniffAgrees returns true when the sniffed content type does not contradict the declared one.
+// A declared type that sniffing recognises must be the sniffed type. Sniffing recognises few types,
+// hence otherwise generic sniffed types agree with the more specific declared types they cover.
+func sniffAgrees(declared, sniffed string) bool {
+	switch {
+	case declared == "" || declared == "application/octet-stream" || declared == sniffed:
+		return true
+	case sniffTypes[declared]:
+		return false
+	case sniffed == "application/octet-stream":
+		return true
+	case sniffed == "text/plain":
+		return strings.HasPrefix(declared, "text/") || declared == "application/json" || declared == "application/xml" ||
+			strings.HasSuffix(declared, "+json") || strings.HasSuffix(declared, "+xml")
+	case sniffed == "text/xml":
+		return strings.HasSuffix(declared, "/xml") || strings.HasSuffix(declared, "+xml")
+	case sniffed == "application/zip":
+		return strings.HasSuffix(declared, "+zip") || strings.HasPrefix(declared, "application/vnd.") || declared == "application/java-archive"
+	}
+	return false
+}
+
+// errMaxBytes is returned by maxReader when the source has more bytes than allowed.
+var errMaxBytes = errors.New("too many bytes")
+
+// maxReader reads at most n bytes from r and fails with errMaxBytes when r has more.
+type maxReader struct {
+	r io.Reader
+	n int64
+}
+
+func (mr *maxReader) Read(b []byte) (int, error) {
+	if mr.n <= 0 {
+		var probe [1]byte
+		n, err := mr.r.Read(probe[:])
+		if n > 0 {
+			return 0, errMaxBytes
+		}
+		return 0, err
+	}
+	if int64(len(b)) > mr.n {
+		b = b[:mr.n]
+	}
+	n, err := mr.r.Read(b)
+	mr.n -= int64(n)
+	return n, err
+}
+
+// bufferBody reads 'src' in memory up to 'spill' bytes and spills to a temporary file beyond it.
+// The temporary file is removed when closed, or else once the HTTP request is handled, see AfterRq.
+// Read errors of 'src' are returned as is, others as internal error status.
+func bufferBody(r *http.Request, src io.Reader, spill int64) (io.Reader, int64, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(src, spill+1))
+	if err != nil {
+		return nil, 0, err
+	}
+	if n <= spill {
+		return bytes.NewReader(buf.Bytes()), n, nil
+	}
+
+	tf, err := ioutil.TempFile("", "kiss-rq-")
+	if err != nil {
+		return nil, 0, status.ErrInternal().WithInternalMessage("creating temporary file for http request body").WithError(err)
+	}
+	tmp := &tempFile{File: tf}
+	AfterRq(r, func() {
+		tmp.Close()
+	})
+	tw := tmpWriter{tf}
+	if _, err := buf.WriteTo(tw); err != nil {
+		return nil, 0, err
+	}
+	m, err := io.Copy(tw, src)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := tf.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, status.ErrInternal().WithInternalMessage("reading temporary file for http request body").WithError(err)
+	}
+	return tmp, n + m, nil
+}
+
+// tempFile is a temporary file that is removed when closed.
+type tempFile struct {
+	*os.File
+	once sync.Once
+	err  error
+}
+
+func (tf *tempFile) Close() error {
+	tf.once.Do(func() {
+		tf.err = tf.File.Close()
+		if err := os.Remove(tf.Name()); err != nil && tf.err == nil {
+			tf.err = err
+		}
+	})
+	return tf.err
+}
+
+// tmpWriter reports write errors of a temporary file as internal error status, to tell them apart from read errors.
+type tmpWriter struct {
+	f *os.File
+}
+
+func (tw tmpWriter) Write(b []byte) (int, error) {
+	n, err := tw.f.Write(b)
+	if err != nil {
+		return n, status.ErrInternal().WithInternalMessage("writing temporary file for http request body").WithError(err)
+	}
+	return n, nil
+}
@@ -0,0 +1,103 @@
+package httputil
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// FormBind populates given variable 'd' from application/x-www-form-urlencoded HTTP request body.
+// Variable 'd' can be a pointer to a struct with fields mapped by struct tag 'form', with the same options,
+// defaults and type conversions as ParamBind.
+//
+// It can also be a map[string]interface{}. Keys present in the map with a non nil value are converted to the type of their value,
+// pointer values are populated in place. Other form fields are set as string, or []string when repeated.
+// Conversion failures are reported as bad request with a detail for each field. Option MaxBytes restricts size of the body.
+func FormBind(d interface{}, oo ...BindOpt) Binder {
+	return formBind{data: d, opts: newBindOpts(oo)}
+}
+
+type formBind struct {
+	data interface{}
+	opts bindOpts
+}
+
+func (fb formBind) ContentType() string {
+	return "application/x-www-form-urlencoded"
+}
+
+func (fb formBind) ContentCompatible(contentType string) bool {
+	return strings.Contains(contentType, fb.ContentType())
+}
+
+func (fb formBind) Bind(r *http.Request) error {
+	if fb.opts.maxBytes > 0 {
+		r.Body = limitBody(r, fb.opts.maxBytes)
+	}
+	if err := r.ParseForm(); err != nil {
+		if errSvc, ok := errTooLarge(err); ok {
+			return errSvc
+		}
+		return status.ErrBadRequest().WithMessage("malformed form in the http request").WithError(err)
+	}
+	form := r.PostForm
+	if m, ok := fb.data.(map[string]interface{}); ok {
+		return bindFormMap(m, form)
+	}
+	return bindTagsErr(fb.data, tagSrc{"form", func(name string) ([]string, bool) {
+		vals, ok := form[name]
+		return vals, ok
+	}})
+}
+
+func (fb formBind) Data() interface{} {
+	return fb.data
+}
+
+// bindFormMap populates map 'm' with form values, converting values of the keys present in the map to the type of their value.
+func bindFormMap(m map[string]interface{}, form map[string][]string) error {
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var dd []*status.StatusDtl
+	for _, k := range keys {
+		vals := form[k]
+		if len(vals) == 0 {
+			continue
+		}
+		cur := m[k]
+		if cur == nil {
+			if len(vals) == 1 {
+				m[k] = vals[0]
+			} else {
+				m[k] = vals
+			}
+			continue
+		}
+		rv := reflect.ValueOf(cur)
+		inPlace := rv.Kind() == reflect.Ptr && !rv.IsNil()
+		if inPlace {
+			rv = rv.Elem()
+		} else {
+			rv = reflect.New(rv.Type()).Elem()
+		}
+		if err := setVals(rv, vals); err != nil {
+			dtl, err := convDtl(k, k, err)
+			if err != nil {
+				return err
+			}
+			dd = append(dd, dtl)
+			continue
+		}
+		if !inPlace {
+			m[k] = rv.Interface()
+		}
+	}
+	return errBadRequestDtls(dd)
+}
@@ -0,0 +1,65 @@
+package httputil
+
+import (
+	"net/http"
+	"net/textproto"
+)
+
+// HdrBind populates given struct 'd' from headers and cookies of the HTTP request.
+// Fields are mapped with struct tags, 'header' for headers and 'cookie' for cookies.
+// Option 'required' reports a missing value as bad request, 'default' tag sets the value when it is missing.
+// A field with both tags takes the header over the cookie.
+//
+//	var rq struct {
+//		TenantID       string `header:"X-Tenant-ID,required"`
+//		IdempotencyKey string `header:"Idempotency-Key"`
+//		Locale         string `header:"Accept-Language" default:"en"`
+//		APIVersion     int    `header:"X-API-Version" default:"1"`
+//		Session        string `cookie:"session"`
+//	}
+//
+// Values are converted to the type of the field with the same rules as ParamBind.
+func HdrBind(d interface{}) Binder {
+	return hdrBind{data: d}
+}
+
+type hdrBind struct {
+	data interface{}
+}
+
+// ContentType returns empty string as headers and cookies are not read from the HTTP request body.
+func (hb hdrBind) ContentType() string {
+	return ""
+}
+
+// ContentCompatible returns true for any Content-Type, including none for GET requests.
+func (hb hdrBind) ContentCompatible(contentType string) bool {
+	return true
+}
+
+func (hb hdrBind) Bind(r *http.Request) error {
+	return bindTagsErr(hb.data, tagSrc{"cookie", cookieSrc(r)}, tagSrc{"header", headerSrc(r)})
+}
+
+func (hb hdrBind) Data() interface{} {
+	return hb.data
+}
+
+// headerSrc returns headers of the http request.
+func headerSrc(r *http.Request) valSrc {
+	return func(name string) ([]string, bool) {
+		vals, ok := r.Header[textproto.CanonicalMIMEHeaderKey(name)]
+		return vals, ok
+	}
+}
+
+// cookieSrc returns cookies of the http request.
+func cookieSrc(r *http.Request) valSrc {
+	return func(name string) ([]string, bool) {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{c.Value}, true
+	}
+}
@@ -0,0 +1,160 @@
+package httputil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/types"
+)
+
+// DefaultSpillBytes is the size beyond which MPStreamBind spills a file to a temporary file.
+const DefaultSpillBytes = 1 << 20
+
+// MaxFileBytes restricts size of each file in a multipart/form-data HTTP request body to 'n' bytes.
+// It also restricts size of each form value.
+func MaxFileBytes(n int64) BindOpt {
+	return func(o *bindOpts) {
+		o.maxFileBytes = n
+	}
+}
+
+// SpillBytes sets the size beyond which a file in the HTTP request body is spilled to a temporary file.
+func SpillBytes(n int64) BindOpt {
+	return func(o *bindOpts) {
+		o.spillBytes = n
+	}
+}
+
+// MPStreamBind populates given map of variable(s) and files from multipart/form-data HTTP request body, reading one part at a time.
+// Only the keys present in the maps are populated, other parts are skipped. All files of a field are bound in order.
+// Values are bound as MPFormBind does: text as string, and JSON into the variable of the key.
+//
+// Files are held in memory up to SpillBytes (DefaultSpillBytes unless set) and spilled to temporary files beyond it.
+// Temporary files are removed once the API handler wrapped by WrapperHandler returns, file readers must not be used after that.
+// Outside of WrapperHandler they are removed when context of the HTTP request is done, a context that is never done
+// (like context.Background) leaves it to the caller to Close each FileObj. Closing a FileObj removes its temporary file early.
+// MaxBytes restricts size of the whole body and MaxFileBytes size of each file, larger requests are rejected as payload too large.
+func MPStreamBind(d map[string]interface{}, f map[string][]*types.FileObj, oo ...BindOpt) Binder {
+	bo := newBindOpts(oo)
+	if bo.spillBytes <= 0 {
+		bo.spillBytes = DefaultSpillBytes
+	}
+	return &mpStreamBind{dataM: d, fileM: f, opts: bo}
+}
+
+type mpStreamBind struct {
+	dataM map[string]interface{}
+	fileM map[string][]*types.FileObj
+	opts  bindOpts
+}
+
+func (ms *mpStreamBind) ContentType() string {
+	return "multipart/form-data"
+}
+
+func (ms *mpStreamBind) ContentCompatible(contentType string) bool {
+	return strings.Contains(contentType, ms.ContentType())
+}
+
+func (ms *mpStreamBind) Bind(r *http.Request) error {
+	if ms.opts.maxBytes > 0 {
+		r.Body = limitBody(r, ms.opts.maxBytes)
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return status.ErrBadRequest().WithMessage("error parsing multi-part message in the http request").WithError(err)
+	}
+	files := make(map[string][]*types.FileObj)
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ms.errRead(err, "")
+		}
+		k := p.FormName()
+		if p.FileName() != "" {
+			if _, ok := ms.fileM[k]; ok {
+				fo, err := ms.readFile(r, p)
+				if err != nil {
+					return err
+				}
+				files[k] = append(files[k], fo)
+			}
+		} else if v, ok := ms.dataM[k]; ok {
+			if err := ms.readVal(p, k, v); err != nil {
+				return err
+			}
+		}
+		if _, err := io.Copy(ioutil.Discard, p); err != nil {
+			return ms.errRead(err, k)
+		}
+	}
+	for k, ff := range files {
+		ms.fileM[k] = ff
+	}
+	return nil
+}
+
+// readVal binds a form value as text or JSON.
+func (ms *mpStreamBind) readVal(p *multipart.Part, k string, v interface{}) error {
+	b, err := ioutil.ReadAll(ms.limit(p))
+	if err != nil {
+		return ms.errRead(err, k)
+	}
+	if _, ok := v.(string); ok {
+		ms.dataM[k] = string(b)
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return status.ErrBadRequest().WithMessage(fmt.Sprintf("error un-marshaling %s part in multi-part message in the http request", k)).WithError(err)
+	}
+	return nil
+}
+
+// readFile reads a file part in memory, spilling it to a temporary file beyond the threshold.
+func (ms *mpStreamBind) readFile(r *http.Request, p *multipart.Part) (*types.FileObj, error) {
+	k := p.FormName()
+	rd, size, err := bufferBody(r, ms.limit(p), ms.opts.spillBytes)
+	if err != nil {
+		return nil, ms.errRead(err, k)
+	}
+	return types.NewFileObjWithMeta(p.FileName(), size, rd, p.Header.Get("Content-Type"), ""), nil
+}
+
+// limit restricts a part to max file bytes when set.
+func (ms *mpStreamBind) limit(p *multipart.Part) io.Reader {
+	if ms.opts.maxFileBytes <= 0 {
+		return p
+	}
+	return &maxReader{r: p, n: ms.opts.maxFileBytes}
+}
+
+func (ms *mpStreamBind) errRead(err error, k string) error {
+	if errSvc, ok := status.FromError(err); ok {
+		return errSvc
+	}
+	if errSvc, ok := errTooLarge(err); ok {
+		return errSvc
+	}
+	if err == errMaxBytes {
+		return status.ErrPayloadTooLarge().WithMessage(fmt.Sprintf("part %s in multi-part message exceeds %d bytes", k, ms.opts.maxFileBytes)).
+			WithDtl(&status.StatusDtl{Field: k, Code: BindCodeTooLarge, Message: "is too large", Params: map[string]interface{}{"max_bytes": ms.opts.maxFileBytes}})
+	}
+	msg := "error reading multi-part message in the http request"
+	if k != "" {
+		msg = fmt.Sprintf("error reading %s part in multi-part message in the http request", k)
+	}
+	return status.ErrBadRequest().WithMessage(msg).WithError(err)
+}
+
+func (ms *mpStreamBind) Data() interface{} {
+	return []interface{}{ms.dataM, ms.fileM}
+}
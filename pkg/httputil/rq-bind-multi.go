@@ -0,0 +1,184 @@
+package httputil
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/codes"
+)
+
+// MultiBind returns a binder that delegates to given binders in order, so that one RqBind call can populate
+// a request from several sources. Values bound by a later binder take precedence over values bound by an earlier one.
+//
+// Binders that read the HTTP request body, i.e. with a non empty ContentType, are applied only when the request has a body
+// (of known length or not),
+// and only the first one compatible with Content-Type of the request is applied.
+// A request body that no binder is compatible with is reported as unsupported media type.
+//
+// Bad request errors with details from all binders are aggregated into one error that lists every bad field.
+func MultiBind(bb ...Binder) Binder {
+	return multiBind{binders: bb}
+}
+
+// StructBind populates given struct 'd' from the HTTP request body (JSON), headers, cookies, query parameters and path variables
+// as per struct tags 'json', 'cookie', 'header', 'query' and 'path'. Precedence, from lowest to highest, is in the same order.
+// Options apply to the JSON body.
+//
+// Fields with a 'header', 'cookie', 'query' or 'path' tag and no 'json' tag are never populated from the body,
+// though encoding/json would fill them by their name, so that clients can't override values set by gateways with the body.
+//
+//	var rq struct {
+//		TenantID string  `header:"X-Tenant-ID,required"`
+//		ID       int     `path:"id"`
+//		DryRun   bool    `query:"dryRun"`
+//		Name     string  `json:"name"`
+//		Amount   float64 `json:"amount"`
+//	}
+func StructBind(d interface{}, oo ...BindOpt) Binder {
+	return MultiBind(structBodyBind{jsonBind{data: d, opts: newBindOpts(oo)}}, HdrBind(d), ParamBind(d))
+}
+
+// nonBodyTags are struct tags of values bound from the HTTP request other than its body.
+var nonBodyTags = []string{"header", "cookie", "query", "path"}
+
+// structBodyBind decodes the HTTP request body like JSONBind, leaving fields that are not bound from the body as they were.
+type structBodyBind struct {
+	jsonBind
+}
+
+func (sb structBodyBind) Bind(r *http.Request) error {
+	rv := reflect.ValueOf(sb.data)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return sb.jsonBind.Bind(r)
+	}
+	// Fields are zeroed while decoding, so that decoding does not write through their pointers, slices or maps.
+	ff := nonBodyFields(rv.Elem())
+	saved := make([]reflect.Value, len(ff))
+	for i, fv := range ff {
+		saved[i] = reflect.New(fv.Type()).Elem()
+		saved[i].Set(fv)
+		fv.Set(reflect.Zero(fv.Type()))
+	}
+	err := sb.jsonBind.Bind(r)
+	for i, fv := range ff {
+		fv.Set(saved[i])
+	}
+	return err
+}
+
+// nonBodyFields returns fields of struct 'sv' that have one of nonBodyTags and no 'json' tag.
+func nonBodyFields(sv reflect.Value) []reflect.Value {
+	var ff []reflect.Value
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			ff = append(ff, nonBodyFields(sv.Field(i))...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if _, ok := sf.Tag.Lookup("json"); ok {
+			continue
+		}
+		for _, tag := range nonBodyTags {
+			if _, ok := sf.Tag.Lookup(tag); ok {
+				ff = append(ff, sv.Field(i))
+				break
+			}
+		}
+	}
+	return ff
+}
+
+type multiBind struct {
+	binders []Binder
+}
+
+// ContentType returns content type of the first binder that reads the HTTP request body.
+func (mb multiBind) ContentType() string {
+	for _, b := range mb.binders {
+		if ct := b.ContentType(); ct != "" {
+			return ct
+		}
+	}
+	return ""
+}
+
+// ContentCompatible returns true when Content-Type is not set, when none of the binders reads the HTTP request body,
+// or when one of them is compatible with given Content-Type.
+func (mb multiBind) ContentCompatible(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	bodyBinders := false
+	for _, b := range mb.binders {
+		if b.ContentType() == "" {
+			continue
+		}
+		bodyBinders = true
+		if b.ContentCompatible(contentType) {
+			return true
+		}
+	}
+	return !bodyBinders
+}
+
+func (mb multiBind) Bind(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	hasBody := r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+
+	var dd []*status.StatusDtl
+	bodyBound, bodyBinders := false, false
+	for _, b := range mb.binders {
+		if b.ContentType() != "" {
+			bodyBinders = true
+			if !hasBody || bodyBound || !b.ContentCompatible(contentType) {
+				continue
+			}
+			bodyBound = true
+		}
+		err := b.Bind(r)
+		if err == nil {
+			continue
+		}
+		errSvc, ok := status.FromError(err)
+		if !ok || !errSvc.IsCode(codes.ErrBadRequest) || len(errSvc.Details) == 0 {
+			return err
+		}
+		dd = append(dd, errSvc.Details...)
+	}
+	if hasBody && bodyBinders && !bodyBound {
+		return status.ErrContentTypeNotSupported()
+	}
+	return errBadRequestDtls(dd)
+}
+
+// Data returns data of the binders, as is when all binders share the same data.
+func (mb multiBind) Data() interface{} {
+	var dd []interface{}
+	for _, b := range mb.binders {
+		d := b.Data()
+		if !containsData(dd, d) {
+			dd = append(dd, d)
+		}
+	}
+	if len(dd) == 1 {
+		return dd[0]
+	}
+	return dd
+}
+
+func containsData(dd []interface{}, d interface{}) bool {
+	if d == nil || !reflect.TypeOf(d).Comparable() {
+		return false
+	}
+	for _, x := range dd {
+		if x != nil && reflect.TypeOf(x) == reflect.TypeOf(d) && x == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package httputil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// Detail codes for HTTP request bodies that cannot be decoded.
+const (
+	BindCodeMalformed    = "bind_malformed"
+	BindCodeUnknownField = "bind_unknown_field"
+	BindCodeTrailingData = "bind_trailing_data"
+	BindCodeTooLarge     = "bind_too_large"
+)
+
+// BindOpt configures how a binder decodes the HTTP request body.
+type BindOpt func(*bindOpts)
+
+type bindOpts struct {
+	maxBytes        int64
+	disallowUnknown bool
+	rejectTrailing  bool
+	useNumber       bool
+	maxFileBytes    int64
+	spillBytes      int64
+}
+
+// MaxBytes restricts size of the HTTP request body to 'n' bytes, a larger body is rejected as payload too large.
+func MaxBytes(n int64) BindOpt {
+	return func(o *bindOpts) {
+		o.maxBytes = n
+	}
+}
+
+// DisallowUnknownFields rejects an HTTP request body with fields that do not map to the variable being populated.
+func DisallowUnknownFields() BindOpt {
+	return func(o *bindOpts) {
+		o.disallowUnknown = true
+	}
+}
+
+// RejectTrailingData rejects an HTTP request body with anything but whitespace after the decoded value.
+func RejectTrailingData() BindOpt {
+	return func(o *bindOpts) {
+		o.rejectTrailing = true
+	}
+}
+
+// UseNumber decodes JSON numbers into interface{} values as json.Number instead of float64.
+func UseNumber() BindOpt {
+	return func(o *bindOpts) {
+		o.useNumber = true
+	}
+}
+
+func newBindOpts(oo []BindOpt) bindOpts {
+	var bo bindOpts
+	for _, o := range oo {
+		o(&bo)
+	}
+	return bo
+}
+
+// body returns the HTTP request body restricted to max bytes when set.
+func (bo bindOpts) body(r *http.Request) io.Reader {
+	if bo.maxBytes <= 0 {
+		return r.Body
+	}
+	return limitBody(r, bo.maxBytes)
+}
+
+// limitBody restricts the HTTP request body to 'n' bytes.
+// Binders are given only the HTTP request, so the response writer is taken from the context where WrapperHandler keeps it.
+// With it the server closes the connection once the limit is exceeded, without it the rest of the body is left unread.
+func limitBody(r *http.Request, n int64) io.ReadCloser {
+	w, _ := r.Context().Value(CtxKeyRsWriter).(http.ResponseWriter)
+	return http.MaxBytesReader(w, r.Body, n)
+}
+
+// errTooLarge returns payload too large error status when 'err' is due to max bytes limit.
+func errTooLarge(err error) (error, bool) {
+	var mbe *http.MaxBytesError
+	if !errors.As(err, &mbe) {
+		return nil, false
+	}
+	return status.ErrPayloadTooLarge().WithMessage(fmt.Sprintf("http request body exceeds %d bytes", mbe.Limit)).WithError(err), true
+}
+
+// decodeJSON decodes the HTTP request body into 'd' as per binding options.
+func decodeJSON(r *http.Request, d interface{}, bo bindOpts) error {
+	body := bo.body(r)
+	dec := json.NewDecoder(body)
+	if bo.disallowUnknown {
+		dec.DisallowUnknownFields()
+	}
+	if bo.useNumber {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(d); err != nil {
+		return errJSON(err, dec.InputOffset())
+	}
+	if !bo.rejectTrailing {
+		return nil
+	}
+	offset := dec.InputOffset()
+	rest := bufio.NewReader(io.MultiReader(dec.Buffered(), body))
+	for {
+		c, err := rest.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errJSON(err, offset)
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return errMalformedJSON(nil, &status.StatusDtl{
+			Code:    BindCodeTrailingData,
+			Message: "unexpected data after the JSON value",
+			Params:  map[string]interface{}{"offset": offset},
+		})
+	}
+}
+
+// errJSON maps JSON decoding errors to a bad request with a detail on the failing field or offset.
+func errJSON(err error, offset int64) error {
+	if errSvc, ok := errTooLarge(err); ok {
+		return errSvc
+	}
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return errMalformedJSON(err, &status.StatusDtl{
+			Code:    BindCodeMalformed,
+			Message: "invalid JSON syntax",
+			Params:  map[string]interface{}{"offset": e.Offset},
+		})
+	case *json.UnmarshalTypeError:
+		typ := jsonType(e.Type)
+		return errMalformedJSON(err, &status.StatusDtl{
+			Field:   e.Field,
+			Code:    BindCodeInvalidType,
+			Message: "must be " + article(typ) + " " + typ,
+			Params:  map[string]interface{}{"type": typ, "offset": e.Offset},
+		})
+	}
+	if err == io.EOF {
+		return errMalformedJSON(err, &status.StatusDtl{Code: BindCodeMalformed, Message: "is empty"})
+	}
+	if err == io.ErrUnexpectedEOF {
+		return errMalformedJSON(err, &status.StatusDtl{
+			Code:    BindCodeMalformed,
+			Message: "unexpected end of JSON input",
+			Params:  map[string]interface{}{"offset": offset},
+		})
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, `json: unknown field "`) {
+		return errMalformedJSON(err, &status.StatusDtl{
+			Field:   strings.TrimSuffix(strings.TrimPrefix(msg, `json: unknown field "`), `"`),
+			Code:    BindCodeUnknownField,
+			Message: "is not allowed",
+		})
+	}
+	return status.ErrBadRequest().WithMessage("error reading the http request").WithError(err)
+}
+
+func errMalformedJSON(err error, dtl *status.StatusDtl) error {
+	errSvc := status.ErrBadRequest().WithMessage("malformed JSON in the http request").WithDtl(dtl)
+	if err != nil {
+		return errSvc.WithError(err)
+	}
+	return errSvc
+}
+
+// jsonType returns JSON type name for a Go type.
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	}
+	return "object"
+}
+
+func article(s string) string {
+	if strings.IndexAny(s[:1], "aeiou") == 0 {
+		return "an"
+	}
+	return "a"
+}
@@ -0,0 +1,61 @@
+package httputil
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ParamBind populates given struct 'd' from path variables and query parameters of the HTTP request.
+// Fields are mapped with struct tags, 'path' for path variables of gorilla mux router and 'query' for query parameters.
+// When a field has both tags, path variable takes precedence.
+// Option 'required' reports a missing value as bad request, 'default' tag sets the value when it is missing.
+//
+//	var rq struct {
+//		ID    int        `path:"id"`
+//		Since types.Date `query:"since"`
+//		Tags  []string   `query:"tag"`
+//		Limit int        `query:"limit" default:"20"`
+//	}
+//
+// Values are converted to the type of the field, which can be string, bool, integer, float, time.Time (RFC 3339),
+// time.Duration, types.Date, encoding.TextUnmarshaler, pointers or slices of these.
+// Slices are populated from repeated or comma separated values.
+// Conversion failures are reported as bad request with a detail for each field.
+func ParamBind(d interface{}) Binder {
+	return paramBind{data: d}
+}
+
+type paramBind struct {
+	data interface{}
+}
+
+// ContentType returns empty string as path variables and query parameters are not read from the HTTP request body.
+func (pb paramBind) ContentType() string {
+	return ""
+}
+
+// ContentCompatible returns true for any Content-Type, including none for GET requests.
+func (pb paramBind) ContentCompatible(contentType string) bool {
+	return true
+}
+
+func (pb paramBind) Bind(r *http.Request) error {
+	return bindTagsErr(pb.data, tagSrc{"query", querySrc(r)}, tagSrc{"path", pathSrc(r)})
+}
+
+func (pb paramBind) Data() interface{} {
+	return pb.data
+}
+
+// pathSrc returns path variables of the http request set by gorilla mux router.
+func pathSrc(r *http.Request) valSrc {
+	vars := mux.Vars(r)
+	return func(name string) ([]string, bool) {
+		v, ok := vars[name]
+		if !ok {
+			return nil, false
+		}
+		return []string{v}, true
+	}
+}
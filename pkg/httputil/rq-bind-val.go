@@ -0,0 +1,63 @@
+package httputil
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status/valderr"
+)
+
+// validateData validates bound data when it implements validation.Validatable.
+// Data of binders that populate several variables, like MPFormBind and MultiBind, is validated variable by variable,
+// and values of a map are reported under their keys.
+// Validation failures are returned as bad request with a detail for each invalid field.
+func validateData(d interface{}) error {
+	valErrs := validation.Errors{}
+	if err := collectValErrs(valErrs, "", d); err != nil {
+		return err
+	}
+	if len(valErrs) == 0 {
+		return nil
+	}
+	return valderr.NewErrStatusWithValErrors(status.ErrBadRequest().WithMessage("invalid values in the http request"), valErrs)
+}
+
+func collectValErrs(valErrs validation.Errors, key string, d interface{}) error {
+	switch v := d.(type) {
+	case []interface{}:
+		for _, x := range v {
+			if err := collectValErrs(valErrs, key, x); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		for k, x := range v {
+			if err := collectValErrs(valErrs, k, x); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := validation.Validate(d)
+	if err == nil {
+		return nil
+	}
+	if ie, ok := err.(validation.InternalError); ok {
+		return status.ErrInternal().WithInternalMessage("validation of the http request data").WithError(ie.InternalError())
+	}
+	nested, ok := err.(validation.Errors)
+	if !ok {
+		valErrs[key] = err
+		return nil
+	}
+	if key != "" {
+		valErrs[key] = nested
+		return nil
+	}
+	for k, e := range nested {
+		valErrs[k] = e
+	}
+	return nil
+}
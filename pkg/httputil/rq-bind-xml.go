@@ -0,0 +1,107 @@
+package httputil
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// XMLBind populates given variable 'd' from application/xml (or text/xml) HTTP request body.
+// Options MaxBytes and RejectTrailingData apply as they do for JSONBind, other options are ignored.
+func XMLBind(d interface{}, oo ...BindOpt) Binder {
+	return xmlBind{data: d, opts: newBindOpts(oo)}
+}
+
+type xmlBind struct {
+	data interface{}
+	opts bindOpts
+}
+
+func (xb xmlBind) ContentType() string {
+	return "application/xml"
+}
+
+func (xb xmlBind) ContentCompatible(contentType string) bool {
+	ct := mediaType(contentType)
+	return ct == "application/xml" || ct == "text/xml" || strings.HasSuffix(ct, "+xml")
+}
+
+func (xb xmlBind) Bind(r *http.Request) error {
+	dec := xml.NewDecoder(xb.opts.body(r))
+	if err := dec.Decode(xb.data); err != nil {
+		return errXML(err, dec.InputOffset())
+	}
+	if !xb.opts.rejectTrailing {
+		return nil
+	}
+	for {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errXML(err, offset)
+		}
+		switch t := tok.(type) {
+		case xml.Comment, xml.ProcInst, xml.Directive:
+			continue
+		case xml.CharData:
+			if len(strings.TrimSpace(string(t))) == 0 {
+				continue
+			}
+		}
+		return errMalformedXML(nil, &status.StatusDtl{
+			Code:    BindCodeTrailingData,
+			Message: "unexpected data after the XML document",
+			Params:  map[string]interface{}{"offset": offset},
+		})
+	}
+}
+
+func (xb xmlBind) Data() interface{} {
+	return xb.data
+}
+
+// errXML maps XML decoding errors to a bad request with a detail on the failing line or offset.
+func errXML(err error, offset int64) error {
+	if errSvc, ok := errTooLarge(err); ok {
+		return errSvc
+	}
+	switch e := err.(type) {
+	case *xml.SyntaxError:
+		return errMalformedXML(err, &status.StatusDtl{
+			Code:    BindCodeMalformed,
+			Message: "invalid XML syntax",
+			Params:  map[string]interface{}{"line": e.Line},
+		})
+	case *strconv.NumError:
+		return errMalformedXML(err, &status.StatusDtl{
+			Code:    BindCodeInvalidType,
+			Message: "value " + strconv.Quote(e.Num) + " is not a valid number",
+			Params:  map[string]interface{}{"offset": offset},
+		})
+	case xml.UnmarshalError:
+		return errMalformedXML(err, &status.StatusDtl{
+			Code:    BindCodeMalformed,
+			Message: "unexpected XML element",
+			Params:  map[string]interface{}{"offset": offset},
+		})
+	}
+	if err == io.EOF {
+		return errMalformedXML(err, &status.StatusDtl{Code: BindCodeMalformed, Message: "is empty"})
+	}
+	return status.ErrBadRequest().WithMessage("error reading the http request").WithError(err)
+}
+
+func errMalformedXML(err error, dtl *status.StatusDtl) error {
+	errSvc := status.ErrBadRequest().WithMessage("malformed XML in the http request").WithDtl(dtl)
+	if err != nil {
+		return errSvc.WithError(err)
+	}
+	return errSvc
+}
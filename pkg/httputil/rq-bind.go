@@ -20,6 +20,24 @@ import (
 //
 // 3) MPFormBind: To be used when Content-Type of HTTP Request is 'multipart/form-data'
 //
+// 4) ParamBind: To be used for path variables and query parameters, for any Content-Type or none
+//
+// 5) HdrBind: To be used for headers and cookies, for any Content-Type or none
+//
+// 6) MultiBind and StructBind: To be used to populate one variable from several of the above sources in a single call
+//
+// 7) MPStreamBind: To be used when Content-Type of HTTP Request is 'multipart/form-data' with large files or several files per field
+//
+// 8) FormBind: To be used when Content-Type of HTTP Request is 'application/x-www-form-urlencoded'
+//
+// 9) FileBind: To be used when the HTTP Request body is a raw file
+//
+// 10) XMLBind: To be used when Content-Type of HTTP Request is 'application/xml' or 'text/xml'
+//
+// 11) ProtoBind, MsgPackBind and CodecBind: To be used when Content-Type of HTTP Request is 'application/x-protobuf', 'application/msgpack' or of another codec
+//
+// Bound data that implements validation.Validatable (ozzo-validation) is validated,
+// failures are returned as bad request with a detail for each invalid field.
 func RqBind(r *http.Request, b Binder) error {
 
 	if !b.ContentCompatible(r.Header.Get("Content-Type")) {
@@ -28,7 +46,7 @@ func RqBind(r *http.Request, b Binder) error {
 	if err := b.Bind(r); err != nil {
 		return err
 	}
-	return nil
+	return validateData(b.Data())
 }
 
 // Binder interface allows binding/populating a variable from the HTTP request body.
@@ -44,8 +62,9 @@ type Binder interface {
 }
 
 // JSONBind populates given variable 'd' from application/json HTTP request body.
-func JSONBind(d interface{}) Binder {
-	return jsonBind{data: d}
+// Options can restrict size of the body, reject unknown fields and trailing data, and decode numbers as json.Number.
+func JSONBind(d interface{}, oo ...BindOpt) Binder {
+	return jsonBind{data: d, opts: newBindOpts(oo)}
 }
 
 // TxtBind populates given variable 'd' from text/plain HTTP request body.
@@ -57,12 +76,14 @@ func TxtBind(d interface{}) Binder {
 // Size of the form-data read is restricted to size 's'.
 // For now only text and JSON variables are supported for structured multipart/form-data.
 // Unstructured data like files will be read into map of type types.FileObj.
+// MPStreamBind suits large files and fields with several files.
 func MPFormBind(d map[string]interface{}, f map[string]*types.FileObj, s int64) *mpFormBind {
 	return &mpFormBind{dataM: d, fileM: f, size: s}
 }
 
 type jsonBind struct {
 	data interface{}
+	opts bindOpts
 }
 
 func (jb jsonBind) ContentType() string {
@@ -77,10 +98,7 @@ func (jb jsonBind) ContentCompatible(contentType string) bool {
 }
 
 func (jb jsonBind) Bind(r *http.Request) error {
-	if err := json.NewDecoder(r.Body).Decode(jb.data); err != nil {
-		return status.ErrBadRequest().WithError(err)
-	}
-	return nil
+	return decodeJSON(r, jb.data, jb.opts)
 }
 
 func (jb jsonBind) Data() interface{} {
@@ -105,7 +123,7 @@ func (tb txtBind) ContentCompatible(contentType string) bool {
 func (tb txtBind) Bind(r *http.Request) error {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return status.ErrBadRequest().WithError(err)
+		return status.ErrBadRequest().WithMessage("error reading the http request").WithError(err)
 	}
 	tb.data = string(body)
 	return nil
@@ -134,7 +152,7 @@ func (mf *mpFormBind) ContentCompatible(contentType string) bool {
 
 func (mf *mpFormBind) Bind(r *http.Request) error {
 	if err := r.ParseMultipartForm(mf.size); err != nil {
-		return status.ErrInternal().WithError(err)
+		return status.ErrInternal().WithMessage("error parsing multi-part message in the http request").WithError(err)
 	}
 	if r.MultipartForm.File != nil {
 		for k, _ := range mf.fileM {
@@ -144,13 +162,13 @@ func (mf *mpFormBind) Bind(r *http.Request) error {
 				//return status.ErrBadRequest().WithError(fmt.Errorf("%s file missing in multi-part message in the http request", k))
 			}
 			f, err := fh[0].Open()
-			defer f.Close()
 			if err != nil {
 				return status.ErrInternal().WithError(err)
 			}
 			b, err := ioutil.ReadAll(f)
+			f.Close()
 			if err != nil {
-				return status.ErrBadRequest().WithError(err)
+				return status.ErrBadRequest().WithMessage(fmt.Sprintf("error reading %s file in multi-part message in the http request", k)).WithError(err)
 			}
 			mf.fileM[k] = types.NewFileObj(fh[0].Filename, fh[0].Size, bytes.NewReader(b))
 		}
@@ -170,7 +188,7 @@ func (mf *mpFormBind) Bind(r *http.Request) error {
 				err = json.Unmarshal([]byte(data[0]), v)
 			}
 			if err != nil {
-				return status.ErrBadRequest().WithError(fmt.Errorf("error un-marshaling %s part in multi-part message in the http request", k))
+				return status.ErrBadRequest().WithMessage(fmt.Sprintf("error un-marshaling %s part in multi-part message in the http request", k)).WithError(err)
 			}
 		}
 	}
@@ -180,50 +198,3 @@ func (mf *mpFormBind) Bind(r *http.Request) error {
 func (mf *mpFormBind) Data() interface{} {
 	return []interface{}{mf.dataM, mf.fileM}
 }
-
-func FileBind(fname string, file *types.FileObj, contTypes ...string) *fileBind {
-	fb := &fileBind{fname: fname, contTypes: contTypes}
-	if len(fb.contTypes) == 0 {
-		fb.contTypes = append(fb.contTypes, "application/binary")
-	}
-	return fb
-}
-
-type fileBind struct {
-	fname     string
-	file      *types.FileObj
-	contTypes []string
-}
-
-func (fb *fileBind) ContentType() string {
-	return "multipart/form-data"
-}
-
-func (fb *fileBind) ContentCompatible(contentType string) bool {
-	for _, acceptableContentType := range fb.contTypes {
-		if strings.Contains(contentType, acceptableContentType) {
-			return true
-		}
-	}
-	return false
-}
-
-func (fb *fileBind) Data() interface{} {
-	return fb.file
-}
-
-func (fb *fileBind) Bind(r *http.Request) error {
-	contType := r.Header.Get("Content-type")
-	fname := fb.fname
-	if !strings.Contains(contType, "application/binary") {
-		ext := strings.Split(contType, "/")[1]
-		fname = strings.Split(fname, ".")[0]
-		fname = strings.Join([]string{fname, ext}, ".")
-	}
-	b, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return status.ErrBadRequest().WithError(err)
-	}
-	fb.file = types.NewFileObj(fname, int64(len(b)), bytes.NewReader(b))
-	return nil
-}
@@ -3,6 +3,7 @@ package httputil
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/google/uuid"
 )
@@ -13,8 +14,58 @@ const (
 	CtxKeyRqID CtxKey = iota
 	CtxKeyToken
 	CtxKeyAuthSubj
+	CtxKeyAfterRq
+	CtxKeyRsWriter
 )
 
+// afterRq holds functions to run once a HTTP request is handled.
+type afterRq struct {
+	mu sync.Mutex
+	ff []func()
+}
+
+func newCtxWithAfterRq(ctx context.Context) (context.Context, *afterRq) {
+	a := &afterRq{}
+	return context.WithValue(ctx, CtxKeyAfterRq, a), a
+}
+
+// run runs registered functions in reverse order of registration.
+func (a *afterRq) run() {
+	a.mu.Lock()
+	ff := a.ff
+	a.ff = nil
+	a.mu.Unlock()
+	for i := len(ff) - 1; i >= 0; i-- {
+		ff[i]()
+	}
+}
+
+// AfterRq registers function 'f' to run once the API handler wrapped by WrapperHandler returns, for example to remove temporary files.
+// Outside of WrapperHandler, 'f' runs when context of the HTTP request is done, which http.Server does once the handler returns.
+// It never runs for a context that cannot be done, like context.Background.
+func AfterRq(r *http.Request, f func()) {
+	ctx := r.Context()
+	if a, ok := ctx.Value(CtxKeyAfterRq).(*afterRq); ok {
+		a.mu.Lock()
+		a.ff = append(a.ff, f)
+		a.mu.Unlock()
+		return
+	}
+	done := ctx.Done()
+	if done == nil {
+		return
+	}
+	go func() {
+		<-done
+		f()
+	}()
+}
+
+// newCtxWithRsWriter keeps response writer of a HTTP request for binders, which are given only the HTTP request.
+func newCtxWithRsWriter(ctx context.Context, w http.ResponseWriter) context.Context {
+	return context.WithValue(ctx, CtxKeyRsWriter, w)
+}
+
 func newCtxWithRqID(ctx context.Context, r *http.Request) context.Context {
 	rqID := r.Header.Get("X-Request-ID")
 	if rqID == "" {
@@ -23,6 +74,15 @@ func newCtxWithRqID(ctx context.Context, r *http.Request) context.Context {
 	return context.WithValue(ctx, CtxKeyRqID, rqID)
 }
 
+// CtxRqID returns request ID that was stored against by WrapperHandler for a HTTP Request.
+func CtxRqID(ctx context.Context) string {
+	v := ctx.Value(CtxKeyRqID)
+	if v == nil {
+		return ""
+	}
+	return v.(string)
+}
+
 // CtxSubject returns subject that was stored against by AuthHandler for a HTTP Request.
 // Subject is extracted from valid token claims.
 func CtxSubject(ctx context.Context) string {
@@ -0,0 +1,162 @@
+package httputil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// CondOpt sets validators of the representation rendered by RsRenderCond.
+type CondOpt func(*condOpts)
+
+type condOpts struct {
+	etag    string
+	lastMod time.Time
+}
+
+// CondETag sets entity tag of the representation, else it is computed from the rendered body.
+func CondETag(tag string) CondOpt {
+	return func(o *condOpts) {
+		o.etag = tag
+	}
+}
+
+// CondLastModified sets last modification time of the resource.
+func CondLastModified(t time.Time) CondOpt {
+	return func(o *condOpts) {
+		o.lastMod = t
+	}
+}
+
+// ETagOf returns the entity tag that RsRenderCond computes for the body rendered by 'rend'.
+// Handlers of writes can use it to compute the tag of the current version for CheckIfMatch.
+func ETagOf(rend Renderer) (string, error) {
+	var buf bytes.Buffer
+	if err := rend.Render(&buf); err != nil {
+		return "", status.ErrInternal().WithError(err)
+	}
+	return etagOf(buf.Bytes()), nil
+}
+
+func etagOf(b []byte) string {
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// RsRenderCond populates http response body like RsRender, for a conditional request 'r'.
+// ETag and Last-Modified headers are set from the options, the entity tag is computed from the rendered body when not given.
+// A GET or HEAD request whose If-None-Match matches the entity tag, or when If-None-Match is absent,
+// whose If-Modified-Since is not before last modification, is answered with 304 Not Modified and no body.
+// Other requests whose If-None-Match matches get precondition failed (412) error status.
+func RsRenderCond(w http.ResponseWriter, r *http.Request, rend Renderer, oo ...CondOpt) error {
+	var o condOpts
+	for _, opt := range oo {
+		opt(&o)
+	}
+	var body []byte
+	etag := o.etag
+	if etag == "" {
+		var buf bytes.Buffer
+		if err := rend.Render(&buf); err != nil {
+			return status.ErrInternal().WithError(err)
+		}
+		body = buf.Bytes()
+		etag = etagOf(body)
+	}
+	etag = quoteETag(etag)
+	w.Header().Set("ETag", etag)
+	if !o.lastMod.IsZero() {
+		w.Header().Set("Last-Modified", o.lastMod.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, o.lastMod) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			return status.ErrPreconditionFailed().WithMessage("resource matches If-None-Match")
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	if body == nil {
+		return RsRender(w, rend)
+	}
+	w.Header().Set("Content-Type", rend.ContentType())
+	rendHeaders(w, rend)
+	if _, err := w.Write(body); err != nil {
+		return status.ErrInternal().WithError(err)
+	}
+	return nil
+}
+
+// CheckIfMatch checks If-Match header of the HTTP request against entity tag 'etag' of the current version of the resource,
+// an empty tag stands for a resource that does not exist. It returns nil when the header is absent or matches,
+// else precondition failed (412) error status with the current entity tag as ETag metadata.
+// Entity tags are compared strongly, so a weak tag never matches.
+func CheckIfMatch(r *http.Request, etag string) error {
+	im := r.Header.Get("If-Match")
+	if im == "" {
+		return nil
+	}
+	if etag != "" {
+		etag = quoteETag(etag)
+		for _, t := range splitETags(im) {
+			if t == "*" || (t == etag && !strings.HasPrefix(t, "W/")) {
+				return nil
+			}
+		}
+	}
+	errSvc := status.ErrPreconditionFailed().WithMessage("resource has changed since it was read")
+	if etag != "" {
+		errSvc = errSvc.WithMeta("ETag", etag)
+	}
+	return errSvc
+}
+
+// CheckIfUnmodifiedSince checks If-Unmodified-Since header of the HTTP request against last modification time of the resource.
+// It returns nil when the header is absent, invalid or not before the last modification, else precondition failed (412) error status.
+// The header is ignored when the request has If-Match, as per RFC 7232.
+func CheckIfUnmodifiedSince(r *http.Request, lastModified time.Time) error {
+	ius := r.Header.Get("If-Unmodified-Since")
+	if ius == "" || r.Header.Get("If-Match") != "" || lastModified.IsZero() {
+		return nil
+	}
+	t, err := http.ParseTime(ius)
+	if err != nil || !lastModified.Truncate(time.Second).After(t) {
+		return nil
+	}
+	return status.ErrPreconditionFailed().WithMessage("resource has changed since it was read").
+		WithMeta("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+}
+
+// notModified returns true when If-None-Match of the request matches entity tag 'etag' (weakly),
+// or when If-None-Match is absent and If-Modified-Since is not before 'lastMod'.
+func notModified(r *http.Request, etag string, lastMod time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range splitETags(inm) {
+			if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastMod.IsZero() || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	return err == nil && !lastMod.Truncate(time.Second).After(t)
+}
+
+func splitETags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
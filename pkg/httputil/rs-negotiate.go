@@ -0,0 +1,113 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// RendFunc describes a signature for functions that return a renderer for given data, like JSONRend, XMLRend and MsgPackRend.
+type RendFunc func(d interface{}) Renderer
+
+// NegotiateRend returns the renderer for data 'd' whose content type best matches Accept header of the HTTP request, among given renderers.
+// Media ranges are weighed by their q-values and the most specific range matching a content type applies.
+// Renderers are in order of preference, the first one is used when Accept header is not set or for ties.
+// When none of the renderers is acceptable, it returns not acceptable (406) error status.
+func NegotiateRend(r *http.Request, d interface{}, rr ...RendFunc) (Renderer, error) {
+	cts := make([]string, len(rr))
+	rends := make([]Renderer, len(rr))
+	for i, rf := range rr {
+		rends[i] = rf(d)
+		cts[i] = rends[i].ContentType()
+	}
+	i, ok := negotiate(r.Header.Get("Accept"), cts)
+	if !ok {
+		return nil, status.ErrNotAcceptable().WithMessage(fmt.Sprintf("available content types are %s", strings.Join(cts, ", ")))
+	}
+	return rends[i], nil
+}
+
+// RsNegotiate populates http response body for data 'd' with the renderer chosen by NegotiateRend.
+func RsNegotiate(w http.ResponseWriter, r *http.Request, d interface{}, rr ...RendFunc) error {
+	rend, err := NegotiateRend(r, d, rr...)
+	if err != nil {
+		return err
+	}
+	return RsRender(w, rend)
+}
+
+// mediaRange is a media range of Accept header with its q-value.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// negotiate returns index of the content type that is best acceptable as per Accept header 'accept'.
+func negotiate(accept string, cts []string) (int, bool) {
+	if len(cts) == 0 {
+		return 0, false
+	}
+	if strings.TrimSpace(accept) == "" {
+		return 0, true
+	}
+	mrs := parseAccept(accept)
+	best, bestQ := 0, 0.0
+	for i, ct := range cts {
+		if q := acceptQ(mrs, mediaType(ct)); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// acceptQ returns q-value of content type 'ct' from the most specific media range that matches it.
+func acceptQ(mrs []mediaRange, ct string) float64 {
+	typ, subtype := ct, ""
+	if i := strings.Index(ct, "/"); i > -1 {
+		typ, subtype = ct[:i], ct[i+1:]
+	}
+	q, specificity := 0.0, -1
+	for _, mr := range mrs {
+		s := -1
+		switch {
+		case mr.typ == typ && mr.subtype == subtype:
+			s = 2
+		case mr.typ == typ && mr.subtype == "*":
+			s = 1
+		case mr.typ == "*" && mr.subtype == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = mr.q, s
+		}
+	}
+	return q
+}
+
+func parseAccept(accept string) []mediaRange {
+	var mrs []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mt := strings.ToLower(strings.TrimSpace(params[0]))
+		if mt == "" {
+			continue
+		}
+		mr := mediaRange{typ: mt, subtype: "*", q: 1}
+		if i := strings.Index(mt, "/"); i > -1 {
+			mr.typ, mr.subtype = mt[:i], mt[i+1:]
+		}
+		for _, p := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(p), "=", 2)
+			if len(kv) == 2 && strings.ToLower(strings.TrimSpace(kv[0])) == "q" {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64); err == nil && q >= 0 && q <= 1 {
+					mr.q = q
+				}
+			}
+		}
+		mrs = append(mrs, mr)
+	}
+	return mrs
+}
@@ -0,0 +1,163 @@
+package httputil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/types"
+)
+
+// FileRendOpt configures a file renderer.
+type FileRendOpt func(*fileRend)
+
+// FileAttachment has clients save the file rather than display it inline, which is the default.
+func FileAttachment() FileRendOpt {
+	return func(fr *fileRend) {
+		fr.disposition = "attachment"
+	}
+}
+
+// FileModTime sets Last-Modified of the file, which is compared with If-Modified-Since of the request.
+func FileModTime(t time.Time) FileRendOpt {
+	return func(fr *fileRend) {
+		fr.modTime = t
+	}
+}
+
+// FileETag sets entity tag of the file, which is compared with If-None-Match of the request.
+// By default the entity tag is the SHA-256 checksum of the file, when known.
+func FileETag(tag string) FileRendOpt {
+	return func(fr *fileRend) {
+		fr.etag = tag
+	}
+}
+
+// FileRend returns a renderer that populates http response for the HTTP request 'r' with contents of given file.
+// Content-Type is the declared type of the file, else sniffed from its data. Content-Disposition carries the file name,
+// encoded as per RFC 5987 when it is not ASCII.
+//
+// When the file reader is seekable, as it is for files bound by MPStreamBind and FileBind, the file is served with http.ServeContent:
+// single and multiple ranges are served, and If-None-Match and If-Modified-Since are answered with 304 Not Modified.
+// Otherwise the file is copied as is, with Content-Length when its size is known.
+// It is to be rendered with RsRender, as the status code depends on the request.
+func FileRend(r *http.Request, f *types.FileObj, oo ...FileRendOpt) Renderer {
+	fr := &fileRend{rq: r, file: f, rd: f.Reader(), contentType: f.ContentType(), disposition: "inline"}
+	if sha := f.SHA256(); sha != "" {
+		fr.etag = sha
+	}
+	for _, o := range oo {
+		o(fr)
+	}
+	if fr.contentType == "" {
+		fr.contentType = fr.sniff()
+	}
+	return fr
+}
+
+type fileRend struct {
+	rq          *http.Request
+	file        *types.FileObj
+	rd          io.Reader
+	contentType string
+	disposition string
+	etag        string
+	modTime     time.Time
+}
+
+func (fr *fileRend) ContentType() string {
+	return fr.contentType
+}
+
+func (fr *fileRend) Render(w io.Writer) error {
+	rw, ok := w.(http.ResponseWriter)
+	if !ok {
+		_, err := io.Copy(w, fr.rd)
+		return err
+	}
+	rw.Header().Set("Content-Disposition", contentDisposition(fr.disposition, fr.file.Name()))
+	if fr.etag != "" {
+		rw.Header().Set("ETag", quoteETag(fr.etag))
+	}
+	if rs, ok := fr.rd.(io.ReadSeeker); ok {
+		http.ServeContent(rw, fr.rq, fr.file.Name(), fr.modTime, rs)
+		return nil
+	}
+	if !fr.modTime.IsZero() {
+		rw.Header().Set("Last-Modified", fr.modTime.UTC().Format(http.TimeFormat))
+	}
+	if fr.file.Size() > 0 {
+		rw.Header().Set("Content-Length", fmt.Sprint(fr.file.Size()))
+	}
+	if _, err := io.Copy(rw, fr.rd); err != nil {
+		return streamFailed(fr.rq.Context(), err)
+	}
+	return nil
+}
+
+// sniff detects content type from the first bytes of the file, leaving the reader at the start of the file.
+func (fr *fileRend) sniff() string {
+	if rs, ok := fr.rd.(io.ReadSeeker); ok {
+		head := make([]byte, sniffLen)
+		n, _ := io.ReadFull(rs, head)
+		if _, err := rs.Seek(0, io.SeekStart); err == nil {
+			return http.DetectContentType(head[:n])
+		}
+		return "application/octet-stream"
+	}
+	br := bufio.NewReaderSize(fr.rd, sniffLen)
+	head, _ := br.Peek(sniffLen)
+	fr.rd = br
+	return http.DetectContentType(head)
+}
+
+// contentDisposition returns Content-Disposition header value with the file name, as is when it is plain ASCII,
+// else with an ASCII fallback and the UTF-8 name encoded as per RFC 5987.
+func contentDisposition(disposition, name string) string {
+	if name == "" {
+		return disposition
+	}
+	fallback, plain := asciiFileName(name)
+	if plain {
+		return fmt.Sprintf("%s; filename=%q", disposition, name)
+	}
+	return fmt.Sprintf("%s; filename=%q; filename*=UTF-8''%s", disposition, fallback, rfc5987Encode(name))
+}
+
+// asciiFileName replaces characters that are not printable ASCII, or quotes and backslashes, with underscores.
+func asciiFileName(name string) (string, bool) {
+	plain := true
+	s := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			plain = false
+			return '_'
+		}
+		return r
+	}, name)
+	return s, plain
+}
+
+// rfc5987Encode percent encodes bytes of 's' that are not attr-char as per RFC 5987.
+func rfc5987Encode(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || strings.IndexByte("!#$&+-.^_`|~", c) > -1 {
+			sb.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&sb, "%%%02X", c)
+	}
+	return sb.String()
+}
+
+// quoteETag returns the entity tag quoted, weak tags and tags that are quoted already are returned as is.
+func quoteETag(tag string) string {
+	if strings.HasPrefix(tag, `"`) || strings.HasPrefix(tag, `W/"`) {
+		return tag
+	}
+	return `"` + tag + `"`
+}
@@ -0,0 +1,247 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// Iterator yields items for streaming renderers, like rows of a report.
+// Next advances to the next item and returns false when there are no more items, when it fails or when 'ctx' is done.
+// Value returns the current item and Err the failure, if any, once Next returned false.
+type Iterator interface {
+	Next(ctx context.Context) bool
+	Value() interface{}
+	Err() error
+}
+
+// ChanIter returns an iterator over items received on channel 'ch' until it is closed.
+// An item that is an error ends the iteration with that error.
+func ChanIter(ch <-chan interface{}) Iterator {
+	return &chanIter{ch: ch}
+}
+
+type chanIter struct {
+	ch  <-chan interface{}
+	val interface{}
+	err error
+}
+
+func (ci *chanIter) Next(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case v, ok := <-ci.ch:
+		if !ok {
+			return false
+		}
+		if err, isErr := v.(error); isErr {
+			ci.err = err
+			return false
+		}
+		ci.val = v
+		return true
+	}
+}
+
+func (ci *chanIter) Value() interface{} {
+	return ci.val
+}
+
+func (ci *chanIter) Err() error {
+	return ci.err
+}
+
+// SSEEvent is an event of a Server-Sent Events stream.
+// Data is sent as is when it is a string, as JSON otherwise. Retry, when set, hints clients how long to wait before reconnecting.
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  interface{}
+	Retry time.Duration
+}
+
+// NDJSONRend returns a renderer that streams items of given iterator as newline delimited JSON (application/x-ndjson),
+// flushing each item to the client as it is written. Streaming stops when 'ctx', typically context of the HTTP request, is done.
+// A failure of the iterator, or an item that cannot be encoded, is reported as a final line {"error": {code, msg, details}},
+// so that the body remains valid.
+func NDJSONRend(ctx context.Context, it Iterator) Renderer {
+	return ndjsonRend{ctx: ctx, it: it}
+}
+
+// SSERend returns a renderer that streams items of given iterator as Server-Sent Events (text/event-stream), flushing each event.
+// Items that are not SSEEvent are sent as data of unnamed events with sequential ids, so that clients can resume with Last-Event-ID.
+// Streaming stops when 'ctx', typically context of the HTTP request, is done.
+// A failure of the iterator, or an item that cannot be encoded, is reported as a final event named 'error' with data {code, msg, details}.
+// Line breaks are stripped from ID and Event of events. Cache-Control and X-Accel-Buffering headers are set when rendered with RsRender.
+func SSERend(ctx context.Context, it Iterator) Renderer {
+	return sseRend{ctx: ctx, it: it}
+}
+
+type ndjsonRend struct {
+	ctx context.Context
+	it  Iterator
+}
+
+func (nr ndjsonRend) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (nr ndjsonRend) Render(w io.Writer) error {
+	for nr.it.Next(nr.ctx) {
+		b, err := json.Marshal(nr.it.Value())
+		if err != nil {
+			nr.fail(w, itemErr(nr.ctx, err))
+			return nil
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return streamFailed(nr.ctx, err)
+		}
+		flush(w)
+	}
+	if err := streamErr(nr.ctx, nr.it); err != nil {
+		nr.fail(w, err)
+	}
+	return nil
+}
+
+// fail writes the final line that reports failure 'err'.
+func (nr ndjsonRend) fail(w io.Writer, err error) {
+	json.NewEncoder(w).Encode(struct {
+		Error status.ErrServiceStatus `json:"error"`
+	}{status.Convert(err)})
+	flush(w)
+}
+
+type sseRend struct {
+	ctx context.Context
+	it  Iterator
+}
+
+func (sr sseRend) ContentType() string {
+	return "text/event-stream"
+}
+
+// Headers stops caches and proxies, like nginx, from holding back events.
+func (sr sseRend) Headers(h http.Header) {
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+}
+
+func (sr sseRend) Render(w io.Writer) error {
+	var seq int64
+	for sr.it.Next(sr.ctx) {
+		ev, ok := sr.it.Value().(SSEEvent)
+		if !ok {
+			seq++
+			ev = SSEEvent{ID: strconv.FormatInt(seq, 10), Data: sr.it.Value()}
+		}
+		data, err := sseData(ev.Data)
+		if err != nil {
+			sr.fail(w, itemErr(sr.ctx, err))
+			return nil
+		}
+		if err := writeSSE(w, ev, data); err != nil {
+			return streamFailed(sr.ctx, err)
+		}
+		flush(w)
+	}
+	if err := streamErr(sr.ctx, sr.it); err != nil {
+		sr.fail(w, err)
+	}
+	return nil
+}
+
+// fail writes the final event named 'error' that reports failure 'err'.
+func (sr sseRend) fail(w io.Writer, err error) {
+	b, _ := json.Marshal(status.Convert(err))
+	writeSSE(w, SSEEvent{Event: "error"}, string(b))
+	flush(w)
+}
+
+// sseData returns data of an event as is when it is a string, as JSON otherwise.
+func sseData(d interface{}) (string, error) {
+	if s, ok := d.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// sseField strips line breaks, and NUL that makes clients ignore an id, so that a value cannot inject fields or events.
+var sseField = strings.NewReplacer("\r", "", "\n", "", "\x00", "")
+
+// writeSSE writes an event with given data in the format of text/event-stream, data with line breaks is sent as several data lines.
+func writeSSE(w io.Writer, ev SSEEvent, data string) error {
+	var sb strings.Builder
+	if id := sseField.Replace(ev.ID); id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", id)
+	}
+	if event := sseField.Replace(ev.Event); event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", event)
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&sb, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	data = strings.Replace(strings.Replace(data, "\r\n", "\n", -1), "\r", "\n", -1)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func flush(w io.Writer) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// streamErr returns failure of the iterator, streams that stopped because the client went away have nothing to report.
+func streamErr(ctx context.Context, it Iterator) error {
+	if ctx.Err() != nil {
+		log.WithField("rqID", CtxRqID(ctx)).Debugln("stream stopped:", ctx.Err())
+		return nil
+	}
+	if err := it.Err(); err != nil {
+		logStreamErr(ctx, status.Convert(err))
+		return err
+	}
+	return nil
+}
+
+// itemErr returns failure to encode an item of the stream as internal error status, and logs it.
+func itemErr(ctx context.Context, err error) status.ErrServiceStatus {
+	errSvc := status.ErrInternal().WithInternalMessage("encoding stream item").WithError(err)
+	logStreamErr(ctx, errSvc)
+	return errSvc
+}
+
+// streamFailed logs a failure to write the stream, most likely because the client went away.
+// The response is already under way, hence the failure is not returned to WrapperHandler.
+func streamFailed(ctx context.Context, err error) error {
+	log.WithField("rqID", CtxRqID(ctx)).WithError(err).Debugln("stream write failed")
+	return nil
+}
+
+func logStreamErr(ctx context.Context, errSvc status.ErrServiceStatus) {
+	l := log.WithFields(errSvc.LogFields()).WithField("rqID", CtxRqID(ctx))
+	if errSvc.Code.HTTPStatusCode() >= http.StatusInternalServerError {
+		l.Errorln("stream failed")
+		return
+	}
+	l.Debugln("stream failed")
+}
@@ -2,14 +2,18 @@ package httputil
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"io"
 	"net/http"
 
+	"github.com/golang/protobuf/proto"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/codec"
 	"github.com/govinda-attal/kiss-lib/pkg/core/status"
 )
 
 // Renderer allows populating http response body with relevant content types.
-// For now only JSON Response objects are supported.
+// JSON, XML, problem details and encodings of package codec are supported.
 type Renderer interface {
 	// Render method renders data variables on the writer (most likely) http.ResponseWriter.
 	Render(w io.Writer) error
@@ -17,15 +21,62 @@ type Renderer interface {
 	ContentType() string
 }
 
+// HeaderRenderer is a Renderer that sets HTTP response headers of its own, like SSERend does.
+// RsRender and RsRenderWithStatus call Headers before the HTTP response status is written.
+type HeaderRenderer interface {
+	Renderer
+	// Headers sets HTTP response headers of the renderer.
+	Headers(h http.Header)
+}
+
+// rendHeaders sets HTTP response headers of renderer 'r' when it has any.
+func rendHeaders(w http.ResponseWriter, r Renderer) {
+	if hr, ok := r.(HeaderRenderer); ok {
+		hr.Headers(w.Header())
+	}
+}
+
 // JSONRend returns a concrete implementation of Renderer that can be used to populate JSON http response for given data.
 func JSONRend(d interface{}) Renderer {
 	return jsonRend{data: d}
 }
 
+// ProblemRend returns a concrete implementation of Renderer that can be used to populate problem details (RFC 7807) http response for given data.
+// Data is expected to be status.Problem or a type that extends it with more members.
+func ProblemRend(d interface{}) Renderer {
+	return problemRend{jsonRend{data: d}}
+}
+
+// XMLRend returns a concrete implementation of Renderer that can be used to populate XML http response for given data.
+func XMLRend(d interface{}) Renderer {
+	return xmlRend{data: d}
+}
+
+// ProblemXMLRend returns a concrete implementation of Renderer that can be used to populate problem details (RFC 7807) http response as XML.
+func ProblemXMLRend(d interface{}) Renderer {
+	return problemXMLRend{xmlRend{data: d}}
+}
+
+// CodecRend returns a concrete implementation of Renderer that populates http response for given data encoded with codec 'c'.
+func CodecRend(c codec.Codec, d interface{}) Renderer {
+	return codecRend{codec: c, data: d}
+}
+
+// ProtoRend returns a concrete implementation of Renderer that can be used to populate application/x-protobuf http response for given message.
+func ProtoRend(m proto.Message) Renderer {
+	return CodecRend(codec.Protobuf, m)
+}
+
+// MsgPackRend returns a concrete implementation of Renderer that can be used to populate application/msgpack http response for given data.
+func MsgPackRend(d interface{}) Renderer {
+	return CodecRend(codec.MsgPack, d)
+}
+
 // RsRender populates http response body where the behaviour is provideed by given renderer implmentation.
 // Content-Type header will also be set as per renderer implementation.
 func RsRender(w http.ResponseWriter, r Renderer) error {
 	w.Header().Add("Content-Type", r.ContentType())
+	rendHeaders(w, r)
 	if err := r.Render(w); err != nil {
 		return status.ErrInternal().WithError(err)
 	}
@@ -37,6 +88,7 @@ func RsRender(w http.ResponseWriter, r Renderer) error {
 // HTTP status code is set with given value.
 func RsRenderWithStatus(w http.ResponseWriter, r Renderer, code int) error {
 	w.Header().Add("Content-Type", r.ContentType())
+	rendHeaders(w, r)
 	httpStatusCode := code
 	w.WriteHeader(httpStatusCode)
 	return RsRender(w, r)
@@ -52,3 +104,47 @@ func (jr jsonRend) ContentType() string {
 func (jr jsonRend) Render(w io.Writer) error {
 	return json.NewEncoder(w).Encode(jr.data)
 }
+
+type problemRend struct {
+	jsonRend
+}
+
+func (pr problemRend) ContentType() string {
+	return "application/problem+json"
+}
+
+type xmlRend struct {
+	data interface{}
+}
+
+func (xr xmlRend) ContentType() string {
+	return "application/xml"
+}
+
+func (xr xmlRend) Render(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(xr.data)
+}
+
+type problemXMLRend struct {
+	xmlRend
+}
+
+func (pr problemXMLRend) ContentType() string {
+	return "application/problem+xml"
+}
+
+type codecRend struct {
+	codec codec.Codec
+	data  interface{}
+}
+
+func (cr codecRend) ContentType() string {
+	return cr.codec.ContentType()
+}
+
+func (cr codecRend) Render(w io.Writer) error {
+	return cr.codec.Encode(w, cr.data)
+}
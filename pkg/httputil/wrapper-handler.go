@@ -3,6 +3,8 @@ package httputil
 import (
 	"net/http"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/govinda-attal/kiss-lib/pkg/core/status"
 )
 
@@ -13,28 +15,105 @@ type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 // DecoratorFunc can be used to add some decorations around real Handlers
 type DecoratorFunc func(f HandlerFunc) HandlerFunc
 
+// ErrRendFunc describes a signature for functions that return the renderer for an error status returned by an API handler.
+type ErrRendFunc func(r *http.Request, errSvc status.ErrServiceStatus) Renderer
+
+// JSONErrRend renders an error status as JSON message - {code, msg, details}. This is the default for WrapperHandler.
+func JSONErrRend(r *http.Request, errSvc status.ErrServiceStatus) Renderer {
+	return JSONRend(&errSvc)
+}
+
+// ProblemErrRend renders an error status as problem details (RFC 7807) with content type 'application/problem+json'.
+// Problem instance is set to the request ID tracked by WrapperHandler.
+func ProblemErrRend(r *http.Request, errSvc status.ErrServiceStatus) Renderer {
+	p := errSvc.Problem(CtxRqID(r.Context()))
+	return ProblemRend(&p)
+}
+
+// XMLErrRend renders an error status as XML message - <status><code/><msg/><details/></status>.
+func XMLErrRend(r *http.Request, errSvc status.ErrServiceStatus) Renderer {
+	return XMLRend(&errSvc)
+}
+
+// ProblemXMLErrRend renders an error status as problem details (RFC 7807) with content type 'application/problem+xml'.
+func ProblemXMLErrRend(r *http.Request, errSvc status.ErrServiceStatus) Renderer {
+	p := errSvc.Problem(CtxRqID(r.Context()))
+	return ProblemXMLRend(&p)
+}
+
+var errRends = []ErrRendFunc{JSONErrRend, XMLErrRend}
+
+// SetErrRend sets the renderer used by WrapperHandler and NotFoundHandler to render error status.
+// It is best set in main package at bootstrap, for example httputil.SetErrRend(httputil.ProblemErrRend).
+func SetErrRend(f ErrRendFunc) {
+	errRends = []ErrRendFunc{f}
+}
+
+// SetErrRends sets the renderers used by WrapperHandler and NotFoundHandler to render error status,
+// the one that best matches Accept header of the HTTP request is chosen as NegotiateRend does.
+// The first renderer is used when none is acceptable. By default JSONErrRend and XMLErrRend are used.
+func SetErrRends(ff ...ErrRendFunc) {
+	if len(ff) == 0 {
+		ff = []ErrRendFunc{JSONErrRend}
+	}
+	errRends = ff
+}
+
+// ErrRends returns the renderers used by WrapperHandler and NotFoundHandler to render error status.
+// They can be restored with SetErrRends, for example defer httputil.SetErrRends(httputil.ErrRends()...).
+func ErrRends() []ErrRendFunc {
+	return append([]ErrRendFunc(nil), errRends...)
+}
+
+// errRend returns the error renderer for the HTTP request, as per its Accept header.
+func errRend(r *http.Request, errSvc status.ErrServiceStatus) Renderer {
+	rends := make([]Renderer, len(errRends))
+	cts := make([]string, len(errRends))
+	for i, f := range errRends {
+		rends[i] = f(r, errSvc)
+		cts[i] = rends[i].ContentType()
+	}
+	i, ok := negotiate(r.Header.Get("Accept"), cts)
+	if !ok {
+		return rends[0]
+	}
+	return rends[i]
+}
+
 // WrapperHandler is wrapper function to wrap API handlers and retuns as http.HandlerFunc.
 // API Handlers may return error, and this wrapper simplifies error handling for API Handlers.
+// Functions registered with AfterRq run once the API handler returns and its error, if any, is rendered.
 func WrapperHandler(f HandlerFunc, dd ...DecoratorFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := newCtxWithRqID(r.Context(), r)
+		ctx, a := newCtxWithAfterRq(newCtxWithRsWriter(newCtxWithRqID(r.Context(), r), w))
+		defer a.run()
 		hf := f
 		for _, d := range dd {
 			hf = d(hf)
 		}
-		err := hf(w, r.WithContext(ctx))
+		r = r.WithContext(ctx)
+		err := hf(w, r)
 		if err != nil {
-			errProcessor(err, w)
+			errProcessor(err, w, r)
 		}
 	}
 }
 
-func errProcessor(err error, w http.ResponseWriter) {
-	w.Header().Add("Content-Type", "application/json")
-	if errSvc, ok := err.(status.ErrServiceStatus); ok {
-		RsRenderWithStatus(w, JSONRend(&errSvc), errSvc.Code.HTTPStatusCode())
+func errProcessor(err error, w http.ResponseWriter, r *http.Request) {
+	errSvc := status.Convert(err)
+	logErr(r, errSvc)
+	for k, v := range errSvc.Meta() {
+		w.Header().Set(k, v)
+	}
+	RsRenderWithStatus(w, errRend(r, errSvc), errSvc.Code.HTTPStatusCode())
+}
+
+// logErr logs the error status in full, including details that are never rendered to clients.
+func logErr(r *http.Request, errSvc status.ErrServiceStatus) {
+	l := log.WithFields(errSvc.LogFields()).WithField("rqID", CtxRqID(r.Context()))
+	if errSvc.Code.HTTPStatusCode() >= http.StatusInternalServerError {
+		l.Errorln(r.Method, r.URL.Path, "failed")
 		return
 	}
-	errSvc := status.ErrInternal().WithMessage(err.Error())
-	RsRender(w, JSONRend(&errSvc))
+	l.Debugln(r.Method, r.URL.Path, "failed")
 }
@@ -39,7 +39,10 @@ func (rv *rsaVerifier) VerifyToken(tokenValue string) (jwt.Claims, error) {
 	})
 	if err != nil {
 		log.Println(err)
-		return nil, status.ErrUnauthorized().WithError(err)
+		if ve, ok := err.(*jwt.ValidationError); ok && ve.Errors&jwt.ValidationErrorExpired != 0 {
+			return nil, ErrTokenExpired().WithError(err)
+		}
+		return nil, ErrTokenInValid().WithError(err)
 	}
 	if !token.Valid {
 		return nil, ErrTokenInValid()
@@ -8,3 +8,8 @@ import (
 func ErrTokenInValid() status.ErrServiceStatus {
 	return status.ErrUnauthorized().WithMessage("Token is not valid")
 }
+
+// ErrTokenExpired represents an expired JWT token.
+func ErrTokenExpired() status.ErrServiceStatus {
+	return status.ErrUnauthorized().WithMessage("Token is expired")
+}
@@ -0,0 +1,33 @@
+package kasync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/govinda-attal/kiss-lib/pkg/core/codec"
+	"github.com/govinda-attal/kiss-lib/pkg/core/status"
+)
+
+// CtxCntType returns content type of the message being handled, as set by the router from message header X-CntType.
+func CtxCntType(ctx context.Context) string {
+	v, _ := ctx.Value(CtxKeyCntType).(string)
+	return v
+}
+
+// Decode populates 'v' from message data with the codec for content type of the message, JSON when the message has none.
+// An unknown content type is reported as unsupported media type and malformed data as bad request.
+func Decode(ctx context.Context, data []byte, v interface{}) error {
+	cntType := CtxCntType(ctx)
+	if cntType == "" {
+		cntType = codec.ContentTypeJSON
+	}
+	c, ok := codec.ByContentType(cntType)
+	if !ok {
+		return status.ErrContentTypeNotSupported().WithMessage(fmt.Sprintf("message content type %s", cntType))
+	}
+	if err := c.Decode(bytes.NewReader(data), v); err != nil {
+		return status.ErrBadRequest().WithMessage("malformed message").WithError(err)
+	}
+	return nil
+}
@@ -149,6 +149,9 @@ func (r *Router) callHandler(msg *kafka.Message) error {
 	if msgName != kasync.MsgHdrValUnk {
 		ctx = context.WithValue(ctx, kasync.CtxKeyMsgName, msgName)
 	}
+	if cntType := headerByKey(msg.Headers, kasync.MsgHdrCntType); cntType != kasync.MsgHdrValUnk {
+		ctx = context.WithValue(ctx, kasync.CtxKeyCntType, cntType)
+	}
 
 	h, err := rg.MsgHandler(msgName)
 	if err != nil {
@@ -183,10 +186,8 @@ func (r *Router) writeErr(msgName string, msgKey []byte, errTopic string, err er
 	if err == nil {
 		return nil
 	}
-	errSvc, ok := err.(status.ErrServiceStatus)
-	if !ok {
-		errSvc = status.ErrInternal().WithError(err)
-	}
+	errSvc := status.Convert(err)
+	log.Println("Error processing message (", msgName, ") key:", string(msgKey), "error:", errSvc.LogFields())
 	b, _ := json.Marshal(errSvc)
 
 	p, err := kafka.NewProducer(r.producerCfg)
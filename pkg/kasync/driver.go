@@ -19,6 +19,7 @@ const (
 const (
 	CtxKeyMsgID   CtxKey = "k-msgid"
 	CtxKeyMsgName CtxKey = "k-msgname"
+	CtxKeyCntType CtxKey = "k-cnttype"
 )
 
 const (
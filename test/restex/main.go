@@ -30,10 +30,14 @@ func NewHandler(g Greeter) *restHandler {
 }
 
 func (rh *restHandler) Hello(w http.ResponseWriter, r *http.Request) error {
-	vars := mux.Vars(r)
-	name := vars["name"]
+	var rq struct {
+		Name string `path:"name"`
+	}
+	if err := httputil.RqBind(r, httputil.ParamBind(&rq)); err != nil {
+		return err
+	}
 
-	msg, err := rh.g.Hello(r.Context(), name)
+	msg, err := rh.g.Hello(r.Context(), rq.Name)
 	if err != nil {
 		return err
 	}